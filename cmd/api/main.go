@@ -2,20 +2,28 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/auth"
 	"github.com/rixtrayker/getemps-service/internal/cache"
 	"github.com/rixtrayker/getemps-service/internal/config"
 	"github.com/rixtrayker/getemps-service/internal/database"
 	"github.com/rixtrayker/getemps-service/internal/handler"
+	"github.com/rixtrayker/getemps-service/internal/masking"
 	"github.com/rixtrayker/getemps-service/internal/middleware"
+	"github.com/rixtrayker/getemps-service/internal/ratelimit"
 	"github.com/rixtrayker/getemps-service/internal/repository"
+	"github.com/rixtrayker/getemps-service/internal/rules"
 	"github.com/rixtrayker/getemps-service/internal/service"
 	"github.com/sirupsen/logrus"
 )
@@ -73,20 +81,102 @@ func main() {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	salaryRepo := repository.NewSalaryRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	oauthClientRepo := repository.NewOAuthClientRepository(db)
+	auditRepo := repository.NewAccessAuditRepository(db)
+	ruleRepo := repository.NewCalculationRuleRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	historyRepo := repository.NewStatusHistoryRepository(db)
+	rankingRepo := repository.NewSalaryRankingRepository(db)
+	departmentRepo := repository.NewDepartmentRepository(db)
+
+	// Load and validate calculation rules before serving any request
+	ruleHistory, err := loadRules(cfg.Rules, ruleRepo)
+	if err != nil {
+		log.Fatalf("Failed to load calculation rules: %v", err)
+	}
+	logger.Infof("Calculation rules %s loaded", strings.Join(ruleHistory.Versions(), ", "))
 
 	// Initialize services
+	calculator := service.NewSalaryCalculatorWithHistory(ruleHistory)
+	calculator.SetCurrency(cfg.Calc.Currency)
 	processStatusService := service.NewProcessStatusService(
 		userRepo,
 		salaryRepo,
+		auditRepo,
+		calculator,
 		appCache,
 		time.Duration(cfg.Cache.TTL)*time.Second,
 	)
+	sufficiency := service.SufficiencyPolicy{
+		MinRecords:         cfg.Calc.Sufficiency.MinRecords,
+		RequireConsecutive: cfg.Calc.Sufficiency.RequireConsecutive,
+		MaxGapMonths:       cfg.Calc.Sufficiency.MaxGapMonths,
+		MaxAgeMonths:       cfg.Calc.Sufficiency.MaxAgeMonths,
+	}
+	processStatusService.SetLogger(logger)
+	processStatusService.SetDefaultWindow(cfg.Calc.WindowMonths)
+	processStatusService.SetSufficiencyPolicy(sufficiency)
+	processStatusService.SetExchangeRateRepository(exchangeRateRepo)
+	processStatusService.SetStatusHistoryRepository(historyRepo)
+
+	// Keep the salary ranking behind percentile ranks current in the background
+	background, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	if cfg.Calc.RankingRefresh > 0 {
+		processStatusService.SetSalaryRankingRepository(rankingRepo)
+		rankingService := service.NewRankingService(userRepo, rankingRepo, processStatusService)
+		go refreshRankings(background, rankingService, time.Duration(cfg.Calc.RankingRefresh)*time.Second, logger)
+	}
+
+	statusHistoryService := service.NewStatusHistoryService(userRepo, historyRepo, auditRepo)
+	simulationService := service.NewSimulationService(calculator)
+	simulationService.SetSufficiencyPolicy(sufficiency)
+	departmentService := service.NewDepartmentService(departmentRepo, userRepo, rankingRepo, auditRepo, cfg.Calc.Currency)
+	batchStatusService := service.NewBatchStatusService(processStatusService, cfg.Batch.Workers)
+
+	// Initialize authentication: signed JWTs (including our own OAuth2
+	// access tokens) first, then stored API keys
+	jwtConfig := auth.JWTConfig{
+		Secret:   cfg.Security.APISecretKey,
+		Issuer:   cfg.Security.JWTIssuer,
+		Audience: cfg.Security.JWTAudience,
+		Leeway:   time.Duration(cfg.Security.JWTLeeway) * time.Second,
+	}
+	if cfg.Security.JWKSSource != "" {
+		jwtConfig.Keys, err = newKeySet(cfg.Security)
+		if err != nil {
+			log.Fatalf("Failed to load JWKS: %v", err)
+		}
+		logger.Info("JWKS verification enabled")
+	}
+	// Issued tokens are always HS256; the JWKS only verifies external tokens
+	tokenIssuer := auth.NewTokenIssuer(jwtConfig, time.Duration(cfg.Security.OAuthTokenTTL)*time.Second)
+
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, cfg.Security.APIKeyPepper)
+	oauthService := service.NewOAuthService(oauthClientRepo, tokenIssuer, cfg.Security.APIKeyPepper)
+
+	// Token tiers come from the OAuth client a token was issued to
+	jwtConfig.Tiers = oauthService
+	tokenVerifier := auth.NewChainVerifier(
+		auth.NewJWTVerifier(jwtConfig),
+		auth.NewAPIKeyVerifier(apiKeyRepo, cfg.Security.APIKeyPepper, logger),
+	)
+	auditService := service.NewAuditService(auditRepo)
 
 	// Initialize handlers
-	employeeHandler := handler.NewEmployeeHandler(processStatusService)
+	masker := newMasker(cfg.Masking)
+	employeeHandler := handler.NewEmployeeHandler(processStatusService, masker)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	oauthHandler := handler.NewOAuthHandler(oauthService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	statusHistoryHandler := handler.NewStatusHistoryHandler(statusHistoryService)
+	simulationHandler := handler.NewSimulationHandler(simulationService)
+	departmentHandler := handler.NewDepartmentHandler(departmentService, masker)
+	batchStatusHandler := handler.NewBatchStatusHandler(batchStatusService, masker, cfg.Batch.MaxSize)
 
 	// Setup router
-	router := setupRouter(employeeHandler, logger, cfg)
+	router := setupRouter(employeeHandler, apiKeyHandler, oauthHandler, auditHandler, statusHistoryHandler, simulationHandler, departmentHandler, batchStatusHandler, tokenVerifier, logger, cfg)
 
 	// Setup HTTP server
 	server := &http.Server{
@@ -95,10 +185,25 @@ func main() {
 		ReadHeaderTimeout: 30 * time.Second,
 	}
 
+	tlsEnabled := cfg.TLS.CertFile != ""
+	if tlsEnabled {
+		server.TLSConfig, err = newTLSConfig(cfg.TLS)
+		if err != nil {
+			log.Fatalf("Failed to configure TLS: %v", err)
+		}
+	}
+
 	// Start server in a goroutine
 	go func() {
-		logger.Infof("Server starting on port %s", cfg.App.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsEnabled {
+			logger.Infof("Server starting with TLS on port %s", cfg.App.Port)
+			err = server.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		} else {
+			logger.Infof("Server starting on port %s", cfg.App.Port)
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
@@ -109,6 +214,7 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopBackground()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -121,22 +227,237 @@ func main() {
 	logger.Info("Server exited")
 }
 
-func setupRouter(employeeHandler *handler.EmployeeHandler, logger *logrus.Logger, cfg *config.Config) *gin.Engine {
+func setupRouter(
+	employeeHandler *handler.EmployeeHandler,
+	apiKeyHandler *handler.APIKeyHandler,
+	oauthHandler *handler.OAuthHandler,
+	auditHandler *handler.AuditHandler,
+	statusHistoryHandler *handler.StatusHistoryHandler,
+	simulationHandler *handler.SimulationHandler,
+	departmentHandler *handler.DepartmentHandler,
+	batchStatusHandler *handler.BatchStatusHandler,
+	tokenVerifier auth.TokenVerifier,
+	logger *logrus.Logger,
+	cfg *config.Config,
+) *gin.Engine {
 	router := gin.New()
 
+	// Only believe X-Forwarded-For from our own proxies, since client IPs key
+	// rate limits and the access audit
+	if err := router.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		logger.Fatalf("Invalid trusted proxies: %v", err)
+	}
+
 	// Add middleware
+	router.Use(middleware.RequestID())
 	router.Use(middleware.Logger(logger))
 	router.Use(middleware.Recovery())
 
 	// Health check endpoint
 	router.GET("/health", employeeHandler.HealthCheck)
 
+	// Requests that do not authenticate, including failed attempts, are
+	// limited by client IP; authenticated ones by identity
+	var passThrough gin.HandlerFunc = func(c *gin.Context) { c.Next() }
+	ipRateLimit, rateLimit := passThrough, passThrough
+	var ipLimiter *middleware.IPLimiter
+	if cfg.RateLimit.Enabled {
+		store := ratelimit.NewMemoryStore()
+		ipLimiter = middleware.NewIPLimiter(store, newIPRateLimitPolicy(cfg.RateLimit))
+		ipRateLimit = ipLimiter.Middleware()
+		rateLimit = middleware.RateLimit(store, newRateLimitPolicy(cfg.RateLimit))
+	}
+
+	// OAuth2 token endpoint authenticates clients itself
+	router.POST("/api/auth/token", ipRateLimit, oauthHandler.Token)
+
 	// API routes with optional authentication
 	api := router.Group("/api")
-	api.Use(middleware.AuthMiddleware(cfg.Security.APISecretKey, cfg.Security.TokenRequired))
+	authOptions := middleware.AuthOptions{
+		TokenRequired: cfg.Security.TokenRequired,
+		Tokens:        tokenVerifier,
+		FailureLimit:  ipLimiter,
+	}
+	if cfg.TLS.ClientCAFile != "" && len(cfg.TLS.ClientIdentities) > 0 {
+		authOptions.Certificates = auth.NewCertificateAuthenticator(cfg.TLS.ClientIdentities, cfg.TLS.ClientIdentityScopes)
+	}
+	if len(cfg.Security.HMACClients) > 0 {
+		authOptions.Signatures = auth.NewSignatureVerifier(auth.SignatureConfig{
+			Secrets: cfg.Security.HMACClients,
+			Scopes:  cfg.Security.HMACScopes,
+			MaxSkew: time.Duration(cfg.Security.HMACMaxSkew) * time.Second,
+		}, auth.NewMemoryNonceStore())
+	}
+
+	api.Use(middleware.AuthMiddleware(authOptions), rateLimit)
 	{
-		api.POST("/GetEmpStatus", employeeHandler.GetEmployeeStatus)
+		api.POST("/GetEmpStatus", middleware.RequireScopes(auth.ScopeStatusRead), employeeHandler.GetEmployeeStatus)
+		api.POST("/GetEmpStatusBatch", middleware.RequireScopes(auth.ScopeStatusRead), batchStatusHandler.GetEmployeeStatusBatch)
+		api.POST("/simulate-status", middleware.RequireScopes(auth.ScopeStatusRead), simulationHandler.SimulateStatus)
+		api.GET("/employees/:nationalNumber/status-history", middleware.RequireScopes(auth.ScopeStatusRead), statusHistoryHandler.List)
+		api.GET("/audit/access", middleware.RequireScopes(auth.ScopeAuditRead), auditHandler.List)
+	}
+
+	// Department and team views read the salary ranking, so they are only
+	// mounted while it is kept current
+	if cfg.Calc.RankingRefresh > 0 {
+		api.GET("/departments/:id/status-summary", middleware.RequireScopes(auth.ScopeStatusRead), departmentHandler.StatusSummary)
+		api.GET("/departments/:id/red-employees", middleware.RequireScopes(auth.ScopeStatusRead), departmentHandler.RedEmployees)
+		api.GET("/employees/:nationalNumber/team/status-summary", middleware.RequireScopes(auth.ScopeStatusRead), departmentHandler.TeamStatusSummary)
+		api.GET("/employees/:nationalNumber/team/red-employees", middleware.RequireScopes(auth.ScopeStatusRead), departmentHandler.TeamRedEmployees)
+	}
+
+	// Administrative routes, only mounted when callers authenticate
+	if cfg.Security.TokenRequired {
+		admin := api.Group("/admin")
+		admin.Use(middleware.RequireScopes(auth.ScopeAdmin))
+		{
+			admin.GET("/api-keys", apiKeyHandler.List)
+			admin.POST("/api-keys", apiKeyHandler.Create)
+			admin.DELETE("/api-keys/:id", apiKeyHandler.Revoke)
+			admin.GET("/oauth-clients", oauthHandler.ListClients)
+			admin.POST("/oauth-clients", oauthHandler.CreateClient)
+			admin.DELETE("/oauth-clients/:clientId", oauthHandler.DeactivateClient)
+		}
 	}
 
 	return router
 }
+
+func loadRules(cfg config.RulesConfig, repo repository.CalculationRuleRepository) (*rules.History, error) {
+	switch cfg.Source {
+	case "file":
+		return rules.LoadHistoryFile(cfg.File)
+	case "database":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		stored, err := repo.ListActive(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		sets := make([]*rules.RuleSet, len(stored))
+		for i, rule := range stored {
+			sets[i], err = rules.Parse([]byte(rule.Definition), "json")
+			if err != nil {
+				return nil, fmt.Errorf("rule set %s: %w", rule.Version, err)
+			}
+			// The column is authoritative for when stored rules take effect
+			sets[i].EffectiveFrom = ""
+			if rule.EffectiveFrom != nil {
+				sets[i].EffectiveFrom = rule.EffectiveFrom.Format("2006-01")
+			}
+		}
+		return rules.NewHistory(sets...)
+	default:
+		return rules.SingleHistory(rules.Default()), nil
+	}
+}
+
+// refreshRankings rebuilds the salary ranking now and then on every interval
+// until the context is cancelled. A failed refresh keeps the previous ranking.
+func refreshRankings(ctx context.Context, rankings *service.RankingService, interval time.Duration, logger *logrus.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ranked, err := rankings.Refresh(ctx)
+		if err != nil {
+			logger.WithError(err).Error("Failed to refresh salary rankings")
+		} else {
+			logger.Infof("Salary rankings refreshed for %d employees", ranked)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newKeySet loads the JWKS from a URL or a local file, depending on the source.
+func newKeySet(cfg config.SecurityConfig) (auth.KeySet, error) {
+	source := cfg.JWKSSource
+	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+		client := &http.Client{Timeout: 10 * time.Second}
+		return auth.NewRemoteJWKS(source, client, time.Duration(cfg.JWKSRefresh)*time.Second)
+	}
+	return auth.NewFileJWKS(source)
+}
+
+func toLimit(rule config.RateLimitRule) ratelimit.Limit {
+	return ratelimit.Limit{Rate: rule.Rate, Burst: rule.Burst}
+}
+
+// newIPRateLimitPolicy applies the per-IP limit everywhere, except on routes
+// with their own limit.
+func newIPRateLimitPolicy(cfg config.RateLimitConfig) *ratelimit.Policy {
+	policy := &ratelimit.Policy{
+		Default: toLimit(cfg.IP),
+		Routes:  make(map[string]ratelimit.Limit, len(cfg.Routes)),
+	}
+	for route, rule := range cfg.Routes {
+		policy.Routes[route] = toLimit(rule)
+	}
+	return policy
+}
+
+func newRateLimitPolicy(cfg config.RateLimitConfig) *ratelimit.Policy {
+	policy := &ratelimit.Policy{
+		Default: toLimit(cfg.Default),
+		Routes:  make(map[string]ratelimit.Limit, len(cfg.Routes)),
+		Tiers:   make(map[string]ratelimit.Limit, len(cfg.Tiers)),
+	}
+	for route, rule := range cfg.Routes {
+		policy.Routes[route] = toLimit(rule)
+	}
+	for tier, rule := range cfg.Tiers {
+		policy.Tiers[tier] = toLimit(rule)
+	}
+	return policy
+}
+
+func newMasker(cfg config.MaskingConfig) *masking.Masker {
+	toRule := func(rule config.MaskRule) masking.Rule {
+		return masking.Rule{Enabled: rule.Enabled, KeepPrefix: rule.KeepPrefix, KeepSuffix: rule.KeepSuffix}
+	}
+
+	return &masking.Masker{
+		Email:          toRule(cfg.Email),
+		Phone:          toRule(cfg.Phone),
+		NationalNumber: toRule(cfg.NationalNumber),
+	}
+}
+
+// newTLSConfig builds the server TLS settings. When a client CA bundle is
+// configured, client certificates are verified against it and, if
+// ClientCertRequired is set, demanded on every connection.
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", cfg.ClientCAFile)
+	}
+
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if cfg.ClientCertRequired {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
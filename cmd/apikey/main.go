@@ -0,0 +1,140 @@
+// Command apikey issues, lists and revokes API keys stored in the api_keys table.
+//
+//	apikey create -owner payroll-batch -scopes "status:read" -tier batch -ttl 2160h
+//	apikey list
+//	apikey revoke -id 3
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/rixtrayker/getemps-service/internal/config"
+	"github.com/rixtrayker/getemps-service/internal/database"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+	"github.com/rixtrayker/getemps-service/internal/service"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := database.NewConnection(database.Config{
+		Host:         cfg.Database.Host,
+		Port:         cfg.Database.Port,
+		User:         cfg.Database.User,
+		Password:     cfg.Database.Password,
+		DBName:       cfg.Database.DBName,
+		SSLMode:      cfg.Database.SSLMode,
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Failed to close database connection: %v", err)
+		}
+	}()
+
+	apiKeys := service.NewAPIKeyService(repository.NewAPIKeyRepository(db), cfg.Security.APIKeyPepper)
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "create":
+		err = create(ctx, apiKeys, os.Args[2:])
+	case "list":
+		err = list(ctx, apiKeys)
+	case "revoke":
+		err = revoke(ctx, apiKeys, os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: apikey create -owner NAME [-scopes \"a b\"] [-tier TIER] [-ttl DURATION]")
+	fmt.Fprintln(os.Stderr, "       apikey list")
+	fmt.Fprintln(os.Stderr, "       apikey revoke -id ID")
+}
+
+func create(ctx context.Context, apiKeys *service.APIKeyService, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	owner := fs.String("owner", "", "consumer the key is issued to")
+	scopes := fs.String("scopes", "", "space-delimited scopes granted to the key")
+	tier := fs.String("tier", "", "rate limit tier")
+	ttl := fs.Duration("ttl", 0, "key lifetime, 0 for no expiry")
+	_ = fs.Parse(args)
+
+	if *owner == "" {
+		return fmt.Errorf("-owner is required")
+	}
+
+	record, key, err := apiKeys.Create(ctx, *owner, auth.ParseScopes(*scopes), *tier, *ttl)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created api key %d for %s\n", record.ID, record.Owner)
+	fmt.Printf("Key (shown once): %s\n", key)
+	return nil
+}
+
+func list(ctx context.Context, apiKeys *service.APIKeyService) error {
+	keys, err := apiKeys.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPREFIX\tOWNER\tSCOPES\tTIER\tEXPIRES\tREVOKED\tLAST USED")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
+			key.ID, key.KeyPrefix, key.Owner, key.Scopes, key.Tier,
+			formatTime(key.ExpiresAt), key.Revoked, formatTime(key.LastUsedAt))
+	}
+	return w.Flush()
+}
+
+func revoke(ctx context.Context, apiKeys *service.APIKeyService, args []string) error {
+	fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+	id := fs.Int64("id", 0, "id of the key to revoke")
+	_ = fs.Parse(args)
+
+	if *id <= 0 {
+		return fmt.Errorf("-id is required")
+	}
+
+	if err := apiKeys.Revoke(ctx, *id); err != nil {
+		return err
+	}
+
+	fmt.Printf("Revoked api key %d\n", *id)
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
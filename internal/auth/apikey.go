@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// APIKeyPrefix marks bearer tokens that are stored API keys rather than JWTs.
+	APIKeyPrefix = "gek_"
+
+	apiKeyDisplayLength = 12
+	lastUsedResolution  = time.Minute
+)
+
+// GenerateAPIKey returns a new random API key and its display prefix.
+func GenerateAPIKey() (key, displayPrefix string, err error) {
+	secret, err := randomSecret(32)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key = APIKeyPrefix + secret
+	return key, key[:apiKeyDisplayLength], nil
+}
+
+// HashAPIKey returns the hash stored for an API key.
+func HashAPIKey(pepper, key string) string {
+	return HashSecret(pepper, key)
+}
+
+// HashSecret returns the hex HMAC-SHA256 of secret under the server-side
+// pepper. Only this hash is ever stored for API keys and client secrets.
+func HashSecret(pepper, secret string) string {
+	mac := hmac.New(sha256.New, []byte(pepper))
+	mac.Write([]byte(secret))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomSecret(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+type APIKeyVerifier struct {
+	repo   repository.APIKeyRepository
+	pepper string
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewAPIKeyVerifier(repo repository.APIKeyRepository, pepper string, logger *logrus.Logger) *APIKeyVerifier {
+	return &APIKeyVerifier{
+		repo:   repo,
+		pepper: pepper,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+func (v *APIKeyVerifier) Verify(ctx context.Context, token string) (*Principal, error) {
+	if !strings.HasPrefix(token, APIKeyPrefix) {
+		return nil, ErrUnrecognizedToken
+	}
+
+	key, err := v.repo.GetByHash(ctx, HashAPIKey(v.pepper, token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: unknown api key", ErrInvalidToken)
+		}
+		return nil, err
+	}
+
+	now := v.now()
+	if key.Revoked {
+		return nil, fmt.Errorf("%w: api key %d revoked", ErrInvalidToken, key.ID)
+	}
+	if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	v.touchLastUsed(key, now)
+
+	return &Principal{
+		Subject: key.Owner,
+		Scopes:  ParseScopes(key.Scopes),
+		Method:  MethodAPIKey,
+		Tier:    key.Tier,
+	}, nil
+}
+
+// touchLastUsed records key usage without blocking the request. Writes are
+// skipped when the stored timestamp is already recent.
+func (v *APIKeyVerifier) touchLastUsed(key *models.APIKey, now time.Time) {
+	if key.LastUsedAt != nil && now.Sub(*key.LastUsedAt) < lastUsedResolution {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := v.repo.TouchLastUsed(ctx, key.ID, now); err != nil && v.logger != nil {
+			v.logger.WithError(err).Warn("Failed to record api key usage")
+		}
+	}()
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return m.Called(ctx, key).Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id int64) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	return m.Called(ctx, id, usedAt).Error(0)
+}
+
+func TestAPIKeyVerifier_Verify(t *testing.T) {
+	const pepper = "test-pepper"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	key, prefix, err := GenerateAPIKey()
+	require.NoError(t, err)
+	require.Equal(t, key[:len(prefix)], prefix)
+	hash := HashAPIKey(pepper, key)
+
+	newVerifier := func(repo *MockAPIKeyRepository) *APIKeyVerifier {
+		verifier := NewAPIKeyVerifier(repo, pepper, nil)
+		verifier.now = func() time.Time { return now }
+		return verifier
+	}
+
+	t.Run("Active key returns principal and records usage", func(t *testing.T) {
+		repo := new(MockAPIKeyRepository)
+		touched := make(chan struct{})
+		repo.On("GetByHash", ctx, hash).Return(&models.APIKey{ID: 7, Owner: "reconciliation", Scopes: "status:read"}, nil)
+		repo.On("TouchLastUsed", mock.Anything, int64(7), now).Return(nil).Run(func(mock.Arguments) { close(touched) })
+
+		principal, err := newVerifier(repo).Verify(ctx, key)
+
+		require.NoError(t, err)
+		assert.Equal(t, "reconciliation", principal.Subject)
+		assert.Equal(t, []string{"status:read"}, principal.Scopes)
+		assert.Equal(t, MethodAPIKey, principal.Method)
+
+		select {
+		case <-touched:
+		case <-time.After(time.Second):
+			t.Fatal("expected last-used timestamp to be recorded")
+		}
+	})
+
+	t.Run("Recently used key is not touched again", func(t *testing.T) {
+		repo := new(MockAPIKeyRepository)
+		lastUsed := now.Add(-10 * time.Second)
+		repo.On("GetByHash", ctx, hash).Return(&models.APIKey{ID: 7, Owner: "reconciliation", LastUsedAt: &lastUsed}, nil)
+
+		_, err := newVerifier(repo).Verify(ctx, key)
+
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Revoked key", func(t *testing.T) {
+		repo := new(MockAPIKeyRepository)
+		repo.On("GetByHash", ctx, hash).Return(&models.APIKey{ID: 7, Revoked: true}, nil)
+
+		_, err := newVerifier(repo).Verify(ctx, key)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Expired key", func(t *testing.T) {
+		repo := new(MockAPIKeyRepository)
+		expiredAt := now.Add(-time.Hour)
+		repo.On("GetByHash", ctx, hash).Return(&models.APIKey{ID: 7, ExpiresAt: &expiredAt}, nil)
+
+		_, err := newVerifier(repo).Verify(ctx, key)
+		assert.ErrorIs(t, err, ErrTokenExpired)
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		repo := new(MockAPIKeyRepository)
+		repo.On("GetByHash", ctx, hash).Return((*models.APIKey)(nil), fmt.Errorf("api key %w", repository.ErrNotFound))
+
+		_, err := newVerifier(repo).Verify(ctx, key)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Non api key tokens are left to other verifiers", func(t *testing.T) {
+		repo := new(MockAPIKeyRepository)
+
+		_, err := newVerifier(repo).Verify(ctx, "eyJhbGciOiJIUzI1NiJ9.e30.sig")
+		assert.ErrorIs(t, err, ErrUnrecognizedToken)
+		repo.AssertNotCalled(t, "GetByHash", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Pepper changes the stored hash", func(t *testing.T) {
+		assert.NotEqual(t, hash, HashAPIKey("other-pepper", key))
+	})
+}
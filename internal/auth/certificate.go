@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"crypto/tls"
+	"errors"
+)
+
+// ErrNoClientCertificate is returned when the connection carries no verified
+// client certificate mapped to a caller.
+var ErrNoClientCertificate = errors.New("no recognized client certificate")
+
+// CertificateAuthenticator maps verified TLS client certificates to callers.
+type CertificateAuthenticator struct {
+	identities map[string]string // certificate common name -> caller identity
+	scopes     []string
+}
+
+func NewCertificateAuthenticator(identities map[string]string, scopes []string) *CertificateAuthenticator {
+	return &CertificateAuthenticator{
+		identities: identities,
+		scopes:     scopes,
+	}
+}
+
+func (a *CertificateAuthenticator) Authenticate(state *tls.ConnectionState) (*Principal, error) {
+	// VerifiedChains is only populated once the chain was checked against the client CA bundle.
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, ErrNoClientCertificate
+	}
+
+	identity, ok := a.identities[state.VerifiedChains[0][0].Subject.CommonName]
+	if !ok {
+		return nil, ErrNoClientCertificate
+	}
+
+	return &Principal{
+		Subject: identity,
+		Scopes:  a.scopes,
+		Method:  MethodMTLS,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateAuthenticator_Authenticate(t *testing.T) {
+	authenticator := NewCertificateAuthenticator(
+		map[string]string{"batch.svc.cluster.local": "payroll-batch"},
+		[]string{ScopeStatusRead},
+	)
+
+	verified := func(commonName string) *tls.ConnectionState {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+
+	t.Run("Mapped subject returns identity", func(t *testing.T) {
+		principal, err := authenticator.Authenticate(verified("batch.svc.cluster.local"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "payroll-batch", principal.Subject)
+		assert.Equal(t, MethodMTLS, principal.Method)
+		assert.True(t, principal.HasScope(ScopeStatusRead))
+	})
+
+	t.Run("Unmapped subject", func(t *testing.T) {
+		_, err := authenticator.Authenticate(verified("unknown.svc.cluster.local"))
+		assert.ErrorIs(t, err, ErrNoClientCertificate)
+	})
+
+	t.Run("Unverified certificate is ignored", func(t *testing.T) {
+		state := verified("batch.svc.cluster.local")
+		state.VerifiedChains = nil
+
+		_, err := authenticator.Authenticate(state)
+		assert.ErrorIs(t, err, ErrNoClientCertificate)
+	})
+
+	t.Run("Plain HTTP connection", func(t *testing.T) {
+		_, err := authenticator.Authenticate(nil)
+		assert.ErrorIs(t, err, ErrNoClientCertificate)
+	})
+}
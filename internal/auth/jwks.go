@@ -0,0 +1,307 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// fileCheckInterval bounds how often a JWKS file is checked for changes.
+	fileCheckInterval = time.Second
+	// minRefetchInterval bounds refetches of a remote JWKS triggered by
+	// unknown key ids, so forged kids cannot hammer the identity provider.
+	minRefetchInterval = 30 * time.Second
+	maxJWKSBytes       = 1 << 20
+)
+
+// KeySet resolves the public keys of asymmetric JWTs by key id.
+type KeySet interface {
+	PublicKey(ctx context.Context, kid string) (interface{}, error)
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS returns the RSA and EC signing keys of a JWKS document by kid.
+// Keys of other types or uses are skipped.
+func parseJWKS(data []byte) (map[string]interface{}, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Kid == "" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		var (
+			key interface{}
+			err error
+		)
+		switch jwk.Kty {
+		case "RSA":
+			key, err = jwk.rsaPublicKey()
+		case "EC":
+			key, err = jwk.ecdsaPublicKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %s: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("modulus: %w", err)
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("exponent: %w", err)
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("unsupported exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k jsonWebKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+
+	x, err := decodeBigInt(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("x: %w", err)
+	}
+	y, err := decodeBigInt(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("y: %w", err)
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) == 0 {
+		return nil, errors.New("malformed base64url value")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
+
+func unknownKey(kid string) error {
+	return fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, kid)
+}
+
+// FileJWKS serves keys from a local JWKS file, reloading it when its
+// modification time changes. A file that fails to parse is ignored and the
+// previous keys stay in use.
+type FileJWKS struct {
+	path    string
+	mu      sync.Mutex
+	keys    map[string]interface{}
+	modTime time.Time
+	checked time.Time
+	now     func() time.Time
+}
+
+func NewFileJWKS(path string) (*FileJWKS, error) {
+	s := &FileJWKS{path: path, now: time.Now}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileJWKS) PublicKey(_ context.Context, kid string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := s.now(); now.Sub(s.checked) >= fileCheckInterval {
+		s.checked = now
+		_ = s.reload()
+	}
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, unknownKey(kid)
+	}
+	return key, nil
+}
+
+func (s *FileJWKS) reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat jwks file: %w", err)
+	}
+	if s.keys != nil && info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read jwks file: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// RemoteJWKS serves keys fetched from a JWKS URL. The document is refetched
+// once it is older than the refresh interval, or early when a token names an
+// unknown key id, which is how rotated keys are picked up. Refetches run in the
+// background without holding the lock: known keys keep being served from the
+// cache meanwhile, and only requests for unknown key ids wait for the result.
+type RemoteJWKS struct {
+	url     string
+	client  *http.Client
+	refresh time.Duration
+	mu      sync.Mutex
+	keys    map[string]interface{}
+	fetched time.Time
+	// refreshing is closed when the refetch in progress, if any, completes
+	refreshing chan struct{}
+	now        func() time.Time
+}
+
+func NewRemoteJWKS(url string, client *http.Client, refresh time.Duration) (*RemoteJWKS, error) {
+	s := &RemoteJWKS{
+		url:     url,
+		client:  client,
+		refresh: refresh,
+		now:     time.Now,
+	}
+	keys, err := s.fetch(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	s.keys = keys
+	s.fetched = s.now()
+	return s, nil
+}
+
+func (s *RemoteJWKS) PublicKey(ctx context.Context, kid string) (interface{}, error) {
+	s.mu.Lock()
+	key, ok := s.keys[kid]
+	age := s.now().Sub(s.fetched)
+	if s.refreshing == nil && (age >= s.refresh || (!ok && age >= minRefetchInterval)) {
+		s.startRefresh(ctx)
+	}
+	refreshing := s.refreshing
+	s.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+	if refreshing == nil {
+		return nil, unknownKey(kid)
+	}
+
+	// The key may have just been rotated in, so wait for the refetch
+	select {
+	case <-refreshing:
+	case <-ctx.Done():
+		return nil, unknownKey(kid)
+	}
+
+	s.mu.Lock()
+	key, ok = s.keys[kid]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, unknownKey(kid)
+	}
+	return key, nil
+}
+
+// startRefresh refetches the document in the background. It must be called
+// with mu held and no refetch in progress.
+func (s *RemoteJWKS) startRefresh(ctx context.Context) {
+	// Failed attempts also count as fetches so an outage is not retried per request
+	s.fetched = s.now()
+	done := make(chan struct{})
+	s.refreshing = done
+
+	// The refetch is shared, so it must outlive the request that started it
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		keys, err := s.fetch(ctx)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// Keep serving the previous keys if the provider is unreachable
+		if err == nil {
+			s.keys = keys
+		}
+		s.refreshing = nil
+		close(done)
+	}()
+}
+
+func (s *RemoteJWKS) fetch(ctx context.Context) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build jwks request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks: %w", err)
+	}
+	return parseJWKS(data)
+}
@@ -0,0 +1,232 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeBigInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": kid,
+		"kty": "RSA",
+		"use": "sig",
+		"n":   encodeBigInt(key.N),
+		"e":   encodeBigInt(big.NewInt(int64(key.E))),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": kid,
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   encodeBigInt(key.X),
+		"y":   encodeBigInt(key.Y),
+	}
+}
+
+func jwksDocument(t *testing.T, keys ...map[string]string) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	require.NoError(t, err)
+	return data
+}
+
+func signTokenWithKid(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestJWTVerifier_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rotatedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, jwksDocument(t, rsaJWK("rsa-1", &rsaKey.PublicKey), ecJWK("ec-1", &ecKey.PublicKey)), 0o600))
+
+	keys, err := NewFileJWKS(path)
+	require.NoError(t, err)
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+
+	verifier := NewJWTVerifier(JWTConfig{
+		Secret:   testSecret,
+		Issuer:   "getemps-auth",
+		Audience: "getemps-service",
+		Keys:     keys,
+	})
+	ctx := context.Background()
+
+	t.Run("RS256 token", func(t *testing.T) {
+		token := signTokenWithKid(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims())
+
+		principal, err := verifier.Verify(ctx, token)
+
+		require.NoError(t, err)
+		assert.Equal(t, "payroll-batch", principal.Subject)
+	})
+
+	t.Run("ES256 token", func(t *testing.T) {
+		token := signTokenWithKid(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims())
+
+		_, err := verifier.Verify(ctx, token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("HS256 tokens still use the shared secret", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims())
+
+		_, err := verifier.Verify(ctx, token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unknown kid", func(t *testing.T) {
+		token := signTokenWithKid(t, jwt.SigningMethodRS256, "rsa-2", rotatedKey, validClaims())
+
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Key of another kid is rejected", func(t *testing.T) {
+		token := signTokenWithKid(t, jwt.SigningMethodRS256, "rsa-1", rotatedKey, validClaims())
+
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Rotated file is reloaded", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, jwksDocument(t, rsaJWK("rsa-2", &rotatedKey.PublicKey)), 0o600))
+		modTime := now.Add(time.Minute)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+		now = now.Add(2 * fileCheckInterval)
+
+		token := signTokenWithKid(t, jwt.SigningMethodRS256, "rsa-2", rotatedKey, validClaims())
+		_, err := verifier.Verify(ctx, token)
+		require.NoError(t, err)
+
+		token = signTokenWithKid(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims())
+		_, err = verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestRemoteJWKS_PublicKey(t *testing.T) {
+	first, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	second, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var document atomic.Value
+	document.Store(jwksDocument(t, rsaJWK("rsa-1", &first.PublicKey)))
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_, _ = w.Write(document.Load().([]byte))
+	}))
+	defer server.Close()
+
+	keys, err := NewRemoteJWKS(server.URL, server.Client(), time.Hour)
+	require.NoError(t, err)
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err = keys.PublicKey(ctx, "rsa-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	document.Store(jwksDocument(t, rsaJWK("rsa-1", &first.PublicKey), rsaJWK("rsa-2", &second.PublicKey)))
+
+	// Unknown kids only trigger a refetch once the minimum interval has passed
+	_, err = keys.PublicKey(ctx, "rsa-2")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	now = now.Add(minRefetchInterval)
+	key, err := keys.PublicKey(ctx, "rsa-2")
+	require.NoError(t, err)
+	assert.Equal(t, &second.PublicKey, key)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func TestRemoteJWKS_RefreshOutsideLock(t *testing.T) {
+	first, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	second, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var document atomic.Value
+	document.Store(jwksDocument(t, rsaJWK("rsa-1", &first.PublicKey)))
+	var fetches int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// Refetches hang until released
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			<-release
+		}
+		_, _ = w.Write(document.Load().([]byte))
+	}))
+	defer server.Close()
+
+	keys, err := NewRemoteJWKS(server.URL, server.Client(), time.Hour)
+	require.NoError(t, err)
+	now := time.Now().Add(time.Hour)
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	document.Store(jwksDocument(t, rsaJWK("rsa-1", &first.PublicKey), rsaJWK("rsa-2", &second.PublicKey)))
+
+	// The expired document starts a refetch but known keys are still served
+	key, err := keys.PublicKey(ctx, "rsa-1")
+	require.NoError(t, err)
+	assert.Equal(t, &first.PublicKey, key)
+
+	// Unknown kids wait for the same refetch instead of starting their own
+	results := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := keys.PublicKey(ctx, "rsa-2")
+			results <- err
+		}()
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = keys.PublicKey(cancelled, "rsa-3")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	close(release)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, <-results)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type JWTConfig struct {
+	Secret   string
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+	// Keys enables RS256 and ES256 tokens, whose kid header selects the key.
+	Keys KeySet
+	// Tiers resolves the rate limit tier of token subjects when set. Tiers are
+	// never taken from the token, since its issuer controls the claims.
+	Tiers TierSource
+}
+
+// TierSource looks up the rate limit tier recorded for a token subject.
+type TierSource interface {
+	TierFor(ctx context.Context, subject string) (string, error)
+}
+
+// Claims are the JWT claims accepted by the service.
+type Claims struct {
+	Scope string `json:"scope,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type JWTVerifier struct {
+	secret []byte
+	keys   KeySet
+	tiers  TierSource
+	parser *jwt.Parser
+}
+
+func NewJWTVerifier(config JWTConfig) *JWTVerifier {
+	methods := []string{jwt.SigningMethodHS256.Alg()}
+	if config.Keys != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(config.Leeway),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &JWTVerifier{
+		secret: []byte(config.Secret),
+		keys:   config.Keys,
+		tiers:  config.Tiers,
+		parser: jwt.NewParser(options...),
+	}
+}
+
+func (v *JWTVerifier) Verify(ctx context.Context, tokenString string) (*Principal, error) {
+	var claims Claims
+	_, err := v.parser.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		// The shared secret only ever verifies HMAC tokens, and JWKS keys
+		// only asymmetric ones, so one cannot stand in for the other.
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			return v.secret, nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("missing kid header")
+		}
+		return v.keys.PublicKey(ctx, kid)
+	})
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		if errors.Is(err, jwt.ErrTokenMalformed) {
+			return nil, ErrUnrecognizedToken
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	principal := &Principal{
+		Subject: claims.Subject,
+		Scopes:  ParseScopes(claims.Scope),
+		Method:  MethodJWT,
+	}
+	if v.tiers != nil {
+		if principal.Tier, err = v.tiers.TierFor(ctx, claims.Subject); err != nil {
+			return nil, err
+		}
+	}
+
+	return principal, nil
+}
+
+// TokenIssuer signs short-lived access tokens that JWTVerifier accepts when
+// configured with the same secret, issuer and audience.
+type TokenIssuer struct {
+	secret   []byte
+	issuer   string
+	audience string
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+func NewTokenIssuer(config JWTConfig, ttl time.Duration) *TokenIssuer {
+	return &TokenIssuer{
+		secret:   []byte(config.Secret),
+		issuer:   config.Issuer,
+		audience: config.Audience,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+func (i *TokenIssuer) TTL() time.Duration {
+	return i.ttl
+}
+
+func (i *TokenIssuer) Issue(subject string, scopes []string) (string, error) {
+	now := i.now()
+	claims := Claims{
+		Scope: strings.Join(scopes, " "),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    i.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.ttl)),
+		},
+	}
+	if i.audience != "" {
+		claims.Audience = jwt.ClaimStrings{i.audience}
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return token, nil
+}
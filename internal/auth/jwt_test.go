@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret-key"
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, claims Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func validClaims() Claims {
+	now := time.Now()
+	return Claims{
+		Scope: "status:read pii:read",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "payroll-batch",
+			Issuer:    "getemps-auth",
+			Audience:  jwt.ClaimStrings{"getemps-service"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	verifier := NewJWTVerifier(JWTConfig{
+		Secret:   testSecret,
+		Issuer:   "getemps-auth",
+		Audience: "getemps-service",
+	})
+	ctx := context.Background()
+
+	t.Run("Valid token returns principal", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims())
+
+		principal, err := verifier.Verify(ctx, token)
+
+		require.NoError(t, err)
+		assert.Equal(t, "payroll-batch", principal.Subject)
+		assert.Equal(t, []string{"status:read", "pii:read"}, principal.Scopes)
+		assert.Equal(t, MethodJWT, principal.Method)
+		assert.True(t, principal.HasScope("pii:read"))
+		assert.False(t, principal.HasScope("admin"))
+	})
+
+	t.Run("Arbitrary string is rejected", func(t *testing.T) {
+		_, err := verifier.Verify(ctx, "xxxxxxxx")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Wrong secret is rejected", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodHS256, []byte("other-secret"), validClaims())
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Other algorithms are rejected", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodHS512, []byte(testSecret), validClaims())
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		claims := validClaims()
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims)
+
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrTokenExpired)
+	})
+
+	t.Run("Missing expiry is rejected", func(t *testing.T) {
+		claims := validClaims()
+		claims.ExpiresAt = nil
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims)
+
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Not yet valid token", func(t *testing.T) {
+		claims := validClaims()
+		claims.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour))
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims)
+
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Issued in the future", func(t *testing.T) {
+		claims := validClaims()
+		claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims)
+
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Wrong issuer", func(t *testing.T) {
+		claims := validClaims()
+		claims.Issuer = "someone-else"
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims)
+
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Wrong audience", func(t *testing.T) {
+		claims := validClaims()
+		claims.Audience = jwt.ClaimStrings{"another-service"}
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims)
+
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Missing subject", func(t *testing.T) {
+		claims := validClaims()
+		claims.Subject = ""
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims)
+
+		_, err := verifier.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+type tierSourceFunc func(ctx context.Context, subject string) (string, error)
+
+func (f tierSourceFunc) TierFor(ctx context.Context, subject string) (string, error) {
+	return f(ctx, subject)
+}
+
+func TestJWTVerifier_Tier(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	// A tier claim is whatever the token's issuer chose to put there
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  "cli_reconciliation",
+		"tier": "batch",
+		"iat":  now.Unix(),
+		"exp":  now.Add(time.Hour).Unix(),
+	}).SignedString([]byte(testSecret))
+	require.NoError(t, err)
+
+	t.Run("Tier claim is ignored", func(t *testing.T) {
+		principal, err := NewJWTVerifier(JWTConfig{Secret: testSecret}).Verify(ctx, token)
+
+		require.NoError(t, err)
+		assert.Empty(t, principal.Tier)
+	})
+
+	t.Run("Tier comes from the tier source", func(t *testing.T) {
+		verifier := NewJWTVerifier(JWTConfig{
+			Secret: testSecret,
+			Tiers: tierSourceFunc(func(_ context.Context, subject string) (string, error) {
+				assert.Equal(t, "cli_reconciliation", subject)
+				return "reporting", nil
+			}),
+		})
+
+		principal, err := verifier.Verify(ctx, token)
+
+		require.NoError(t, err)
+		assert.Equal(t, "reporting", principal.Tier)
+	})
+
+	t.Run("Tier source failure is not an invalid token", func(t *testing.T) {
+		verifier := NewJWTVerifier(JWTConfig{
+			Secret: testSecret,
+			Tiers: tierSourceFunc(func(context.Context, string) (string, error) {
+				return "", errors.New("connection refused")
+			}),
+		})
+
+		_, err := verifier.Verify(ctx, token)
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInvalidToken)
+	})
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// ClientIDPrefix marks OAuth2 client identifiers issued by the service.
+const ClientIDPrefix = "cli_"
+
+// GenerateClientCredentials returns a new OAuth2 client id and secret.
+func GenerateClientCredentials() (clientID, clientSecret string, err error) {
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate client id: %w", err)
+	}
+
+	clientSecret, err = randomSecret(32)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate client secret: %w", err)
+	}
+
+	return ClientIDPrefix + hex.EncodeToString(idBytes), clientSecret, nil
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+
+	// ErrUnrecognizedToken is returned by a verifier for tokens in a format it
+	// does not handle, so a ChainVerifier can try the next one.
+	ErrUnrecognizedToken = fmt.Errorf("%w: unrecognized token format", ErrInvalidToken)
+)
+
+// Authentication methods reported on a Principal.
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+	MethodHMAC   = "hmac"
+	MethodMTLS   = "mtls"
+	MethodNone   = "none"
+)
+
+// Scopes understood by the service. ScopeAdmin satisfies every scope check.
+const (
+	ScopeStatusRead     = "status:read"
+	ScopeEmployeesWrite = "employees:write"
+	ScopeAuditRead      = "audit:read"
+	ScopePIIRead        = "pii:read"
+	ScopeAdmin          = "admin"
+)
+
+// Principal is the verified identity of a caller.
+type Principal struct {
+	Subject string
+	Scopes  []string
+	Method  string
+	// Tier selects the caller's rate limits; empty means the default tier.
+	Tier string
+}
+
+// HasScope reports whether the principal was granted the given scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the principal may act with the given scope, either
+// because it was granted directly or because the principal is an admin.
+func (p *Principal) Allows(scope string) bool {
+	return p.HasScope(scope) || p.HasScope(ScopeAdmin)
+}
+
+// AnonymousPrincipal is attached to requests when authentication is disabled.
+// It may read but never administer, so credentials cannot be minted without
+// authenticating.
+func AnonymousPrincipal() *Principal {
+	return &Principal{
+		Subject: "anonymous",
+		Scopes:  []string{ScopeStatusRead, ScopeAuditRead, ScopePIIRead},
+		Method:  MethodNone,
+	}
+}
+
+// TokenVerifier verifies a bearer token and returns the caller it identifies.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*Principal, error)
+}
+
+// ChainVerifier tries each verifier in order until one recognizes the token.
+type ChainVerifier struct {
+	verifiers []TokenVerifier
+}
+
+func NewChainVerifier(verifiers ...TokenVerifier) *ChainVerifier {
+	return &ChainVerifier{verifiers: verifiers}
+}
+
+func (c *ChainVerifier) Verify(ctx context.Context, token string) (*Principal, error) {
+	for _, verifier := range c.verifiers {
+		principal, err := verifier.Verify(ctx, token)
+		if errors.Is(err, ErrUnrecognizedToken) {
+			continue
+		}
+		return principal, err
+	}
+	return nil, ErrUnrecognizedToken
+}
+
+// ParseScopes splits a space-delimited scope string as used by OAuth2.
+func ParseScopes(scope string) []string {
+	return strings.Fields(scope)
+}
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnonymousPrincipal(t *testing.T) {
+	principal := AnonymousPrincipal()
+
+	assert.Equal(t, MethodNone, principal.Method)
+	for _, scope := range []string{ScopeStatusRead, ScopeAuditRead, ScopePIIRead} {
+		assert.True(t, principal.Allows(scope), scope)
+	}
+	assert.False(t, principal.Allows(ScopeAdmin))
+	assert.False(t, principal.Allows(ScopeEmployeesWrite))
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureVerifier_Verify(t *testing.T) {
+	const secret = "batch-shared-secret"
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"NationalNumber": "NAT1001"}`)
+
+	newVerifier := func() *SignatureVerifier {
+		verifier := NewSignatureVerifier(SignatureConfig{
+			Secrets: map[string]string{"batch": secret},
+			Scopes:  []string{ScopeStatusRead},
+			MaxSkew: 5 * time.Minute,
+		}, NewMemoryNonceStore())
+		verifier.now = func() time.Time { return now }
+		return verifier
+	}
+
+	signed := func(at time.Time, nonce string) SignedRequest {
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+		return SignedRequest{
+			KeyID:     "batch",
+			Method:    "POST",
+			Path:      "/api/GetEmpStatus",
+			Timestamp: timestamp,
+			Nonce:     nonce,
+			Signature: SignRequest(secret, "POST", "/api/GetEmpStatus", timestamp, nonce, body),
+			Body:      body,
+		}
+	}
+
+	t.Run("Valid signature returns principal", func(t *testing.T) {
+		principal, err := newVerifier().Verify(signed(now, "n-1"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "batch", principal.Subject)
+		assert.Equal(t, MethodHMAC, principal.Method)
+		assert.True(t, principal.HasScope(ScopeStatusRead))
+	})
+
+	t.Run("Tampered body is rejected", func(t *testing.T) {
+		req := signed(now, "n-1")
+		req.Body = []byte(`{"NationalNumber": "NAT1002"}`)
+
+		_, err := newVerifier().Verify(req)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Tampered path is rejected", func(t *testing.T) {
+		req := signed(now, "n-1")
+		req.Path = "/api/admin/api-keys"
+
+		_, err := newVerifier().Verify(req)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Tampered query is rejected", func(t *testing.T) {
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		req := SignedRequest{
+			KeyID:     "batch",
+			Method:    "GET",
+			Path:      "/api/audit/access?caller=other",
+			Timestamp: timestamp,
+			Nonce:     "n-1",
+			Signature: SignRequest(secret, "GET", "/api/audit/access?caller=batch", timestamp, "n-1", nil),
+		}
+
+		_, err := newVerifier().Verify(req)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Unknown key id", func(t *testing.T) {
+		req := signed(now, "n-1")
+		req.KeyID = "someone"
+
+		_, err := newVerifier().Verify(req)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Stale and future timestamps", func(t *testing.T) {
+		verifier := newVerifier()
+
+		_, err := verifier.Verify(signed(now.Add(-6*time.Minute), "n-1"))
+		assert.ErrorIs(t, err, ErrStaleRequest)
+
+		_, err = verifier.Verify(signed(now.Add(6*time.Minute), "n-2"))
+		assert.ErrorIs(t, err, ErrStaleRequest)
+	})
+
+	t.Run("Replayed nonce is rejected", func(t *testing.T) {
+		verifier := newVerifier()
+
+		_, err := verifier.Verify(signed(now, "n-1"))
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(signed(now, "n-1"))
+		assert.ErrorIs(t, err, ErrReplayedRequest)
+
+		_, err = verifier.Verify(signed(now, "n-2"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Forged request does not burn the nonce", func(t *testing.T) {
+		verifier := newVerifier()
+		forged := signed(now, "n-1")
+		forged.Signature = "00"
+
+		_, err := verifier.Verify(forged)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+
+		_, err = verifier.Verify(signed(now, "n-1"))
+		assert.NoError(t, err)
+	})
+}
@@ -2,25 +2,37 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Cache    CacheConfig
-	Security SecurityConfig
-	Logging  LoggingConfig
+	App       AppConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Cache     CacheConfig
+	Security  SecurityConfig
+	TLS       TLSConfig
+	RateLimit RateLimitConfig
+	Masking   MaskingConfig
+	Rules     RulesConfig
+	Calc      CalculationConfig
+	Batch     BatchConfig
+	Logging   LoggingConfig
 }
 
 type AppConfig struct {
 	Port string
 	Env  string
+	// TrustedProxies are the IPs or CIDRs whose X-Forwarded-For headers are
+	// believed when resolving client IPs; none by default
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -49,6 +61,91 @@ type CacheConfig struct {
 type SecurityConfig struct {
 	APISecretKey  string
 	TokenRequired bool
+	JWTIssuer     string
+	JWTAudience   string
+	JWTLeeway     int    // seconds
+	JWKSSource    string // JWKS file path or http(s) URL
+	JWKSRefresh   int    // seconds, for URLs
+	APIKeyPepper  string
+	HMACClients   map[string]string // key id -> shared secret
+	HMACScopes    []string
+	HMACMaxSkew   int // seconds
+	OAuthTokenTTL int // seconds
+}
+
+type TLSConfig struct {
+	CertFile             string
+	KeyFile              string
+	ClientCAFile         string
+	ClientCertRequired   bool
+	ClientIdentities     map[string]string // certificate common name -> caller identity
+	ClientIdentityScopes []string
+}
+
+type RateLimitConfig struct {
+	Enabled bool
+	Default RateLimitRule
+	IP      RateLimitRule            // per client IP, before authentication
+	Routes  map[string]RateLimitRule // route pattern -> limit
+	Tiers   map[string]RateLimitRule // client tier -> limit
+}
+
+// RateLimitRule is written as "rate:burst" in the environment, with rate in
+// requests per second.
+type RateLimitRule struct {
+	Rate  float64
+	Burst int
+}
+
+// MaskingConfig controls how personal data is redacted for callers without
+// the pii:read scope.
+type MaskingConfig struct {
+	Email          MaskRule
+	Phone          MaskRule
+	NationalNumber MaskRule
+}
+
+// MaskRule is written as "prefix:suffix" in the environment, the number of
+// characters left visible at each end, or "off" to disable masking.
+type MaskRule struct {
+	Enabled    bool
+	KeepPrefix int
+	KeepSuffix int
+}
+
+// RulesConfig selects where salary calculation rules come from: the built-in
+// defaults, a JSON/YAML file or the calculation_rules table.
+type RulesConfig struct {
+	Source string // default, file or database
+	File   string
+}
+
+// CalculationConfig holds calculation defaults. WindowMonths limits status
+// lookups to the trailing months, including the current one; zero uses the
+// full salary history. Salaries are converted to Currency, an ISO 4217 code.
+type CalculationConfig struct {
+	WindowMonths int
+	Currency     string
+	Sufficiency  SufficiencyConfig
+	// RankingRefresh is the seconds between salary ranking refreshes, zero to
+	// disable percentile ranks
+	RankingRefresh int
+}
+
+// SufficiencyConfig is the salary data required before a status is given.
+// Zero MaxGapMonths or MaxAgeMonths disables that check.
+type SufficiencyConfig struct {
+	MinRecords         int
+	RequireConsecutive bool
+	MaxGapMonths       int
+	MaxAgeMonths       int
+}
+
+// BatchConfig bounds batch status lookups: at most MaxSize national numbers
+// per request, looked up by Workers concurrent workers.
+type BatchConfig struct {
+	MaxSize int
+	Workers int
 }
 
 type LoggingConfig struct {
@@ -76,10 +173,46 @@ func Load() (*Config, error) {
 	viper.SetDefault("REDIS_DB", 0)
 	viper.SetDefault("CACHE_ENABLED", true)
 	viper.SetDefault("CACHE_TTL", 300)
+	viper.SetDefault("TLS_CERT_FILE", "")
+	viper.SetDefault("TLS_KEY_FILE", "")
+	viper.SetDefault("TLS_CLIENT_CA_FILE", "")
+	viper.SetDefault("TLS_CLIENT_CERT_REQUIRED", false)
+	viper.SetDefault("TLS_CLIENT_IDENTITIES", "")
+	viper.SetDefault("TLS_CLIENT_SCOPES", "status:read")
+	viper.SetDefault("TRUSTED_PROXIES", "")
+	viper.SetDefault("RATE_LIMIT_ENABLED", true)
+	viper.SetDefault("RATE_LIMIT_DEFAULT", "10:20")
+	viper.SetDefault("RATE_LIMIT_IP", "20:40")
+	viper.SetDefault("RATE_LIMIT_ROUTES", "")
+	viper.SetDefault("RATE_LIMIT_TIERS", "")
+	viper.SetDefault("PII_MASK_EMAIL", "1:0")
+	viper.SetDefault("PII_MASK_PHONE", "3:3")
+	viper.SetDefault("PII_MASK_NATIONAL_NUMBER", "3:2")
+	viper.SetDefault("RULES_SOURCE", "default")
+	viper.SetDefault("RULES_FILE", "")
+	viper.SetDefault("CALCULATION_WINDOW_MONTHS", 0)
+	viper.SetDefault("REPORTING_CURRENCY", "JOD")
+	viper.SetDefault("SUFFICIENCY_MIN_RECORDS", 3)
+	viper.SetDefault("SUFFICIENCY_REQUIRE_CONSECUTIVE", false)
+	viper.SetDefault("SUFFICIENCY_MAX_GAP_MONTHS", 0)
+	viper.SetDefault("SUFFICIENCY_MAX_AGE_MONTHS", 0)
+	viper.SetDefault("RANKING_REFRESH_INTERVAL", 3600)
+	viper.SetDefault("BATCH_MAX_SIZE", 100)
+	viper.SetDefault("BATCH_WORKERS", 8)
 	viper.SetDefault("LOG_LEVEL", "info")
 	viper.SetDefault("LOG_TO_DB", true)
 	viper.SetDefault("API_SECRET_KEY", "your-super-secret-key-change-in-production")
 	viper.SetDefault("TOKEN_REQUIRED", true)
+	viper.SetDefault("JWT_ISSUER", "")
+	viper.SetDefault("JWT_AUDIENCE", "")
+	viper.SetDefault("JWT_LEEWAY", 30)
+	viper.SetDefault("JWKS_SOURCE", "")
+	viper.SetDefault("JWKS_REFRESH", 600)
+	viper.SetDefault("API_KEY_PEPPER", "")
+	viper.SetDefault("HMAC_CLIENTS", "")
+	viper.SetDefault("HMAC_SCOPES", "status:read")
+	viper.SetDefault("HMAC_MAX_SKEW", 300)
+	viper.SetDefault("OAUTH_TOKEN_TTL", 300)
 
 	viper.AutomaticEnv()
 
@@ -87,6 +220,8 @@ func Load() (*Config, error) {
 		App: AppConfig{
 			Port: getEnvStr("APP_PORT", "8080"),
 			Env:  getEnvStr("APP_ENV", "development"),
+			// Comma-separated IPs or CIDRs
+			TrustedProxies: strings.Fields(strings.ReplaceAll(getEnvStr("TRUSTED_PROXIES", ""), ",", " ")),
 		},
 		Database: DatabaseConfig{
 			Host:         getEnvStr("DB_HOST", "localhost"),
@@ -111,6 +246,43 @@ func Load() (*Config, error) {
 		Security: SecurityConfig{
 			APISecretKey:  getEnvStr("API_SECRET_KEY", "your-super-secret-key-change-in-production"),
 			TokenRequired: getEnvBool("TOKEN_REQUIRED", true),
+			JWTIssuer:     getEnvStr("JWT_ISSUER", ""),
+			JWTAudience:   getEnvStr("JWT_AUDIENCE", ""),
+			JWTLeeway:     getEnvInt("JWT_LEEWAY", 30),
+			JWKSSource:    getEnvStr("JWKS_SOURCE", ""),
+			JWKSRefresh:   getEnvInt("JWKS_REFRESH", 600),
+			APIKeyPepper:  getEnvStr("API_KEY_PEPPER", ""),
+			HMACClients:   getEnvMap("HMAC_CLIENTS"),
+			HMACScopes:    strings.Fields(getEnvStr("HMAC_SCOPES", "status:read")),
+			HMACMaxSkew:   getEnvInt("HMAC_MAX_SKEW", 300),
+			OAuthTokenTTL: getEnvInt("OAUTH_TOKEN_TTL", 300),
+		},
+		Rules: RulesConfig{
+			Source: getEnvStr("RULES_SOURCE", "default"),
+			File:   getEnvStr("RULES_FILE", ""),
+		},
+		Calc: CalculationConfig{
+			WindowMonths: getEnvInt("CALCULATION_WINDOW_MONTHS", 0),
+			Currency:     strings.ToUpper(getEnvStr("REPORTING_CURRENCY", "JOD")),
+			Sufficiency: SufficiencyConfig{
+				MinRecords:         getEnvInt("SUFFICIENCY_MIN_RECORDS", 3),
+				RequireConsecutive: getEnvBool("SUFFICIENCY_REQUIRE_CONSECUTIVE", false),
+				MaxGapMonths:       getEnvInt("SUFFICIENCY_MAX_GAP_MONTHS", 0),
+				MaxAgeMonths:       getEnvInt("SUFFICIENCY_MAX_AGE_MONTHS", 0),
+			},
+			RankingRefresh: getEnvInt("RANKING_REFRESH_INTERVAL", 3600),
+		},
+		Batch: BatchConfig{
+			MaxSize: getEnvInt("BATCH_MAX_SIZE", 100),
+			Workers: getEnvInt("BATCH_WORKERS", 8),
+		},
+		TLS: TLSConfig{
+			CertFile:             getEnvStr("TLS_CERT_FILE", ""),
+			KeyFile:              getEnvStr("TLS_KEY_FILE", ""),
+			ClientCAFile:         getEnvStr("TLS_CLIENT_CA_FILE", ""),
+			ClientCertRequired:   getEnvBool("TLS_CLIENT_CERT_REQUIRED", false),
+			ClientIdentities:     getEnvMap("TLS_CLIENT_IDENTITIES"),
+			ClientIdentityScopes: strings.Fields(getEnvStr("TLS_CLIENT_SCOPES", "status:read")),
 		},
 		Logging: LoggingConfig{
 			Level: getEnvStr("LOG_LEVEL", "info"),
@@ -118,6 +290,18 @@ func Load() (*Config, error) {
 		},
 	}
 
+	rateLimit, err := loadRateLimitConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.RateLimit = *rateLimit
+
+	masking, err := loadMaskingConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Masking = *masking
+
 	if err := validateConfig(config); err != nil {
 		return nil, err
 	}
@@ -150,6 +334,128 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvMap parses a comma-separated list of key=value pairs. Only the first
+// "=" separates a key from its value, so values may contain "=".
+func getEnvMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && name != "" && value != "" {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+func loadRateLimitConfig() (*RateLimitConfig, error) {
+	defaultRule, err := parseRateLimitRule(getEnvStr("RATE_LIMIT_DEFAULT", "10:20"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_DEFAULT: %w", err)
+	}
+
+	ipRule, err := parseRateLimitRule(getEnvStr("RATE_LIMIT_IP", "20:40"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_IP: %w", err)
+	}
+
+	routes, err := parseRateLimitRules(getEnvMap("RATE_LIMIT_ROUTES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_ROUTES: %w", err)
+	}
+
+	tiers, err := parseRateLimitRules(getEnvMap("RATE_LIMIT_TIERS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_TIERS: %w", err)
+	}
+
+	return &RateLimitConfig{
+		Enabled: getEnvBool("RATE_LIMIT_ENABLED", true),
+		Default: defaultRule,
+		IP:      ipRule,
+		Routes:  routes,
+		Tiers:   tiers,
+	}, nil
+}
+
+func parseRateLimitRules(raw map[string]string) (map[string]RateLimitRule, error) {
+	rules := make(map[string]RateLimitRule, len(raw))
+	for name, value := range raw {
+		rule, err := parseRateLimitRule(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		rules[name] = rule
+	}
+	return rules, nil
+}
+
+func parseRateLimitRule(value string) (RateLimitRule, error) {
+	rateStr, burstStr, ok := strings.Cut(value, ":")
+	if !ok {
+		return RateLimitRule{}, fmt.Errorf("expected rate:burst, got %q", value)
+	}
+
+	rate, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || rate <= 0 {
+		return RateLimitRule{}, fmt.Errorf("rate must be a positive number, got %q", rateStr)
+	}
+
+	burst, err := strconv.Atoi(burstStr)
+	if err != nil || burst < 1 {
+		return RateLimitRule{}, fmt.Errorf("burst must be a positive integer, got %q", burstStr)
+	}
+
+	return RateLimitRule{Rate: rate, Burst: burst}, nil
+}
+
+func loadMaskingConfig() (*MaskingConfig, error) {
+	var config MaskingConfig
+	fields := []struct {
+		key          string
+		defaultValue string
+		rule         *MaskRule
+	}{
+		{"PII_MASK_EMAIL", "1:0", &config.Email},
+		{"PII_MASK_PHONE", "3:3", &config.Phone},
+		{"PII_MASK_NATIONAL_NUMBER", "3:2", &config.NationalNumber},
+	}
+
+	for _, field := range fields {
+		rule, err := parseMaskRule(getEnvStr(field.key, field.defaultValue))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", field.key, err)
+		}
+		*field.rule = rule
+	}
+
+	return &config, nil
+}
+
+func parseMaskRule(value string) (MaskRule, error) {
+	if value == "off" {
+		return MaskRule{}, nil
+	}
+
+	prefixStr, suffixStr, ok := strings.Cut(value, ":")
+	if !ok {
+		return MaskRule{}, fmt.Errorf("expected prefix:suffix or off, got %q", value)
+	}
+
+	prefix, err := strconv.Atoi(prefixStr)
+	if err != nil || prefix < 0 {
+		return MaskRule{}, fmt.Errorf("prefix must be a non-negative integer, got %q", prefixStr)
+	}
+
+	suffix, err := strconv.Atoi(suffixStr)
+	if err != nil || suffix < 0 {
+		return MaskRule{}, fmt.Errorf("suffix must be a non-negative integer, got %q", suffixStr)
+	}
+
+	return MaskRule{Enabled: true, KeepPrefix: prefix, KeepSuffix: suffix}, nil
+}
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
 func validateConfig(config *Config) error {
 	if config.Database.Host == "" {
 		return fmt.Errorf("database host is required")
@@ -165,5 +471,63 @@ func validateConfig(config *Config) error {
 			return fmt.Errorf("API secret key must be set in production")
 		}
 	}
+	for _, proxy := range config.App.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("trusted proxy %q must be an IP or CIDR", proxy)
+			}
+		}
+	}
+	if config.Security.JWKSSource != "" && config.Security.JWKSRefresh <= 0 {
+		return fmt.Errorf("JWKS refresh interval must be positive")
+	}
+	switch config.Rules.Source {
+	case "default", "database":
+	case "file":
+		if config.Rules.File == "" {
+			return fmt.Errorf("rules file is required when rules source is file")
+		}
+	default:
+		return fmt.Errorf("rules source must be default, file or database")
+	}
+	if config.Calc.WindowMonths < 0 {
+		return fmt.Errorf("calculation window must not be negative")
+	}
+	if config.Calc.Sufficiency.MinRecords < 1 {
+		return fmt.Errorf("sufficiency minimum records must be at least 1")
+	}
+	if config.Calc.Sufficiency.MaxGapMonths < 0 || config.Calc.Sufficiency.MaxAgeMonths < 0 {
+		return fmt.Errorf("sufficiency gap and age limits must not be negative")
+	}
+	if config.Calc.RankingRefresh < 0 {
+		return fmt.Errorf("ranking refresh interval must not be negative")
+	}
+	if config.Batch.MaxSize < 1 || config.Batch.Workers < 1 {
+		return fmt.Errorf("batch max size and workers must be at least 1")
+	}
+	if !currencyPattern.MatchString(config.Calc.Currency) {
+		return fmt.Errorf("reporting currency must be a three-letter ISO 4217 code")
+	}
+	if config.Security.HMACMaxSkew <= 0 {
+		return fmt.Errorf("HMAC max skew must be positive")
+	}
+	if config.Security.OAuthTokenTTL <= 0 {
+		return fmt.Errorf("OAuth token TTL must be positive")
+	}
+	if (config.TLS.CertFile == "") != (config.TLS.KeyFile == "") {
+		return fmt.Errorf("TLS cert file and key file must be set together")
+	}
+	if config.TLS.ClientCAFile != "" && config.TLS.CertFile == "" {
+		return fmt.Errorf("TLS client CA requires TLS cert and key files")
+	}
+	if config.TLS.ClientCertRequired && config.TLS.ClientCAFile == "" {
+		return fmt.Errorf("requiring client certificates needs a TLS client CA file")
+	}
+	if config.Security.APIKeyPepper == "" && config.App.Env == "production" {
+		return fmt.Errorf("API key pepper must be set in production")
+	}
+	if config.Security.JWTLeeway < 0 {
+		return fmt.Errorf("JWT leeway must not be negative")
+	}
 	return nil
 }
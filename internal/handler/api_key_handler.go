@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/service"
+	"github.com/rixtrayker/getemps-service/internal/validator"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request format",
+		})
+		return
+	}
+
+	if err := validator.ValidateCreateAPIKeyRequest(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ttl := time.Duration(req.TTLHours) * time.Hour
+	apiKey, key, err := h.apiKeyService.Create(c.Request.Context(), req.Owner, req.Scopes, req.Tier, ttl)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.CreateAPIKeyResponse{
+		APIKey: *apiKey,
+		Key:    key,
+	})
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.apiKeyService.List(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if keys == nil {
+		keys = []models.APIKey{}
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid API key id",
+		})
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request.Context(), id); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/service"
+	"github.com/rixtrayker/getemps-service/internal/validator"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// List answers who looked up an employee, or whom a caller looked up.
+func (h *AuditHandler) List(c *gin.Context) {
+	var query models.AccessAuditQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := validator.ValidateAccessAuditQuery(query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	entries, err := h.auditService.List(c.Request.Context(), query)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if entries == nil {
+		entries = []models.AccessAudit{}
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/rixtrayker/getemps-service/internal/masking"
+	"github.com/rixtrayker/getemps-service/internal/middleware"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/service"
+	"github.com/rixtrayker/getemps-service/internal/validator"
+)
+
+type BatchStatusHandler struct {
+	batchService *service.BatchStatusService
+	masker       *masking.Masker
+	maxSize      int
+}
+
+func NewBatchStatusHandler(batchService *service.BatchStatusService, masker *masking.Masker, maxSize int) *BatchStatusHandler {
+	return &BatchStatusHandler{
+		batchService: batchService,
+		masker:       masker,
+		maxSize:      maxSize,
+	}
+}
+
+// GetEmployeeStatusBatch looks up several employees at once. Each result
+// carries the employee or the error its own lookup would have returned.
+func (h *BatchStatusHandler) GetEmployeeStatusBatch(c *gin.Context) {
+	var req models.BatchStatusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request format",
+		})
+		return
+	}
+
+	if err := validator.ValidateBatchStatusRequest(req, h.maxSize); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	results, err := h.batchService.GetEmployeeStatuses(requestContext(c), req.NationalNumbers)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// Redact contact details for callers not cleared for personal data
+	if principal, ok := middleware.GetPrincipal(c); !ok || !principal.Allows(auth.ScopePIIRead) {
+		for i := range results {
+			if results[i].Employee != nil {
+				results[i].Employee = h.masker.Mask(results[i].Employee)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, models.BatchStatusResponse{Results: results})
+}
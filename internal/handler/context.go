@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/middleware"
+	"github.com/rixtrayker/getemps-service/internal/service"
+)
+
+// requestContext returns the request context annotated with the caller, so
+// services can attribute the work they do.
+func requestContext(c *gin.Context) context.Context {
+	info := service.RequestInfo{
+		ClientIP:  c.ClientIP(),
+		RequestID: middleware.GetRequestID(c),
+	}
+	if principal, ok := middleware.GetPrincipal(c); ok {
+		info.Caller = principal.Subject
+		info.AuthMethod = principal.Method
+	}
+	return service.WithRequestInfo(c.Request.Context(), info)
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/rixtrayker/getemps-service/internal/masking"
+	"github.com/rixtrayker/getemps-service/internal/middleware"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/service"
+	"github.com/rixtrayker/getemps-service/internal/validator"
+)
+
+type DepartmentHandler struct {
+	departmentService *service.DepartmentService
+	masker            *masking.Masker
+}
+
+func NewDepartmentHandler(departmentService *service.DepartmentService, masker *masking.Masker) *DepartmentHandler {
+	return &DepartmentHandler{
+		departmentService: departmentService,
+		masker:            masker,
+	}
+}
+
+// StatusSummary returns the status distribution and average salary of a
+// department, including its sub-departments.
+func (h *DepartmentHandler) StatusSummary(c *gin.Context) {
+	id, ok := departmentID(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.departmentService.StatusSummary(requestContext(c), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// RedEmployees lists the RED employees of a department, including its
+// sub-departments.
+func (h *DepartmentHandler) RedEmployees(c *gin.Context) {
+	id, ok := departmentID(c)
+	if !ok {
+		return
+	}
+
+	employees, err := h.departmentService.RedEmployees(requestContext(c), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	h.respondEmployees(c, employees)
+}
+
+// TeamStatusSummary returns the status distribution and average salary of
+// everyone reporting to a manager, directly or indirectly.
+func (h *DepartmentHandler) TeamStatusSummary(c *gin.Context) {
+	nationalNumber, ok := managerNationalNumber(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.departmentService.TeamStatusSummary(requestContext(c), nationalNumber)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// TeamRedEmployees lists the RED employees reporting to a manager, directly
+// or indirectly.
+func (h *DepartmentHandler) TeamRedEmployees(c *gin.Context) {
+	nationalNumber, ok := managerNationalNumber(c)
+	if !ok {
+		return
+	}
+
+	employees, err := h.departmentService.TeamRedEmployees(requestContext(c), nationalNumber)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	h.respondEmployees(c, employees)
+}
+
+func (h *DepartmentHandler) respondEmployees(c *gin.Context, employees []models.DepartmentEmployee) {
+	// Redact national numbers for callers not cleared for personal data
+	if principal, ok := middleware.GetPrincipal(c); !ok || !principal.Allows(auth.ScopePIIRead) {
+		for i := range employees {
+			employees[i].NationalNumber = h.masker.NationalNumber.Apply(employees[i].NationalNumber)
+		}
+	}
+
+	c.JSON(http.StatusOK, employees)
+}
+
+func departmentID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid department id",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func managerNationalNumber(c *gin.Context) (string, bool) {
+	nationalNumber := c.Param("nationalNumber")
+	if !validator.IsValidNationalNumber(nationalNumber) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid national number format",
+		})
+		return "", false
+	}
+	return nationalNumber, true
+}
@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/rixtrayker/getemps-service/internal/masking"
+	"github.com/rixtrayker/getemps-service/internal/middleware"
 	"github.com/rixtrayker/getemps-service/internal/models"
 	"github.com/rixtrayker/getemps-service/internal/service"
 	"github.com/rixtrayker/getemps-service/internal/validator"
@@ -12,11 +15,13 @@ import (
 
 type EmployeeHandler struct {
 	processStatusService *service.ProcessStatusService
+	masker               *masking.Masker
 }
 
-func NewEmployeeHandler(processStatusService *service.ProcessStatusService) *EmployeeHandler {
+func NewEmployeeHandler(processStatusService *service.ProcessStatusService, masker *masking.Masker) *EmployeeHandler {
 	return &EmployeeHandler{
 		processStatusService: processStatusService,
+		masker:               masker,
 	}
 }
 
@@ -40,12 +45,24 @@ func (h *EmployeeHandler) GetEmployeeStatus(c *gin.Context) {
 	}
 
 	// Process request
-	employeeInfo, err := h.processStatusService.GetEmployeeStatus(c.Request.Context(), req.NationalNumber)
+	employeeInfo, err := h.processStatusService.GetEmployeeStatusWithOptions(requestContext(c), req.NationalNumber, service.StatusOptions{
+		Explain: req.Explain,
+		Window: service.Window{
+			From:       models.Period{Year: req.FromYear, Month: req.FromMonth},
+			To:         models.Period{Year: req.ToYear, Month: req.ToMonth},
+			LastMonths: req.LastMonths,
+		},
+	})
 	if err != nil {
-		h.handleError(c, err)
+		handleError(c, err)
 		return
 	}
 
+	// Redact contact details for callers not cleared for personal data
+	if principal, ok := middleware.GetPrincipal(c); !ok || !principal.Allows(auth.ScopePIIRead) {
+		employeeInfo = h.masker.Mask(employeeInfo)
+	}
+
 	// Return success response
 	c.JSON(http.StatusOK, employeeInfo)
 }
@@ -57,17 +74,3 @@ func (h *EmployeeHandler) HealthCheck(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, response)
 }
-
-func (h *EmployeeHandler) handleError(c *gin.Context, err error) {
-	if appErr, ok := err.(*service.AppError); ok {
-		c.JSON(appErr.Code, models.ErrorResponse{
-			Error: appErr.Message,
-		})
-		return
-	}
-
-	// Log internal errors but don't expose them
-	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-		Error: "Internal server error",
-	})
-}
\ No newline at end of file
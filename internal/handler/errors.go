@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/service"
+)
+
+func handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*service.AppError); ok {
+		c.JSON(appErr.Code, models.ErrorResponse{
+			Error:   appErr.Message,
+			Details: appErr.Details,
+		})
+		return
+	}
+
+	// Log internal errors but don't expose them
+	_ = c.Error(err)
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Error: "Internal server error",
+	})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/service"
+	"github.com/rixtrayker/getemps-service/internal/validator"
+)
+
+type OAuthHandler struct {
+	oauthService *service.OAuthService
+}
+
+func NewOAuthHandler(oauthService *service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+	}
+}
+
+// Token implements the OAuth2 client_credentials grant. Clients authenticate
+// with HTTP Basic or with client_id/client_secret form fields.
+func (h *OAuthHandler) Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	grantType := c.PostForm("grant_type")
+	if grantType == "" {
+		writeOAuthError(c, &service.OAuthError{
+			Status:      http.StatusBadRequest,
+			Code:        service.OAuthInvalidRequest,
+			Description: "grant_type is required",
+		})
+		return
+	}
+	if grantType != "client_credentials" {
+		writeOAuthError(c, &service.OAuthError{
+			Status:      http.StatusBadRequest,
+			Code:        service.OAuthUnsupportedGrantType,
+			Description: "Only client_credentials is supported",
+		})
+		return
+	}
+
+	clientID, clientSecret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientID = c.PostForm("client_id")
+		clientSecret = c.PostForm("client_secret")
+	}
+	if clientID == "" || clientSecret == "" {
+		writeOAuthError(c, &service.OAuthError{
+			Status:      http.StatusUnauthorized,
+			Code:        service.OAuthInvalidClient,
+			Description: "Client credentials are required",
+		})
+		return
+	}
+
+	scopes := auth.ParseScopes(c.PostForm("scope"))
+	token, err := h.oauthService.IssueClientCredentialsToken(c.Request.Context(), clientID, clientSecret, scopes)
+	if err != nil {
+		var oauthErr *service.OAuthError
+		if errors.As(err, &oauthErr) {
+			writeOAuthError(c, oauthErr)
+			return
+		}
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+func (h *OAuthHandler) CreateClient(c *gin.Context) {
+	var req models.CreateOAuthClientRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request format",
+		})
+		return
+	}
+
+	if err := validator.ValidateCreateOAuthClientRequest(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	client, secret, err := h.oauthService.RegisterClient(c.Request.Context(), req.Name, req.Scopes, req.Tier)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.CreateOAuthClientResponse{
+		Client:       *client,
+		ClientSecret: secret,
+	})
+}
+
+func (h *OAuthHandler) ListClients(c *gin.Context) {
+	clients, err := h.oauthService.ListClients(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if clients == nil {
+		clients = []models.OAuthClient{}
+	}
+
+	c.JSON(http.StatusOK, clients)
+}
+
+func (h *OAuthHandler) DeactivateClient(c *gin.Context) {
+	if err := h.oauthService.DeactivateClient(c.Request.Context(), c.Param("clientId")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func writeOAuthError(c *gin.Context, err *service.OAuthError) {
+	if err.Status == http.StatusUnauthorized {
+		c.Header("WWW-Authenticate", `Basic realm="token"`)
+	}
+	c.JSON(err.Status, models.OAuthErrorResponse{
+		Error:            err.Code,
+		ErrorDescription: err.Description,
+	})
+}
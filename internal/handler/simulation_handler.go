@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/service"
+	"github.com/rixtrayker/getemps-service/internal/validator"
+)
+
+type SimulationHandler struct {
+	simulationService *service.SimulationService
+}
+
+func NewSimulationHandler(simulationService *service.SimulationService) *SimulationHandler {
+	return &SimulationHandler{
+		simulationService: simulationService,
+	}
+}
+
+// SimulateStatus previews the status a hypothetical salary series would get.
+func (h *SimulationHandler) SimulateStatus(c *gin.Context) {
+	var req models.SimulateStatusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request format",
+		})
+		return
+	}
+
+	if err := validator.ValidateSimulateStatusRequest(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.simulationService.SimulateStatus(req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
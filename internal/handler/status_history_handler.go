@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/service"
+	"github.com/rixtrayker/getemps-service/internal/validator"
+)
+
+type StatusHistoryHandler struct {
+	historyService *service.StatusHistoryService
+}
+
+func NewStatusHistoryHandler(historyService *service.StatusHistoryService) *StatusHistoryHandler {
+	return &StatusHistoryHandler{
+		historyService: historyService,
+	}
+}
+
+// List returns the statuses computed for an employee over time.
+func (h *StatusHistoryHandler) List(c *gin.Context) {
+	nationalNumber := c.Param("nationalNumber")
+	var query models.StatusHistoryQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := validator.ValidateStatusHistoryQuery(nationalNumber, query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	snapshots, err := h.historyService.List(requestContext(c), nationalNumber, query)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if snapshots == nil {
+		snapshots = []models.StatusSnapshot{}
+	}
+
+	c.JSON(http.StatusOK, snapshots)
+}
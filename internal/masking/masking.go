@@ -0,0 +1,60 @@
+package masking
+
+import (
+	"strings"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+const maskChar = '*'
+
+// Rule redacts a value, keeping KeepPrefix leading and KeepSuffix trailing
+// characters. Values too short to keep anything are masked entirely.
+type Rule struct {
+	Enabled    bool
+	KeepPrefix int
+	KeepSuffix int
+}
+
+func (r Rule) Apply(value string) string {
+	if !r.Enabled || value == "" {
+		return value
+	}
+
+	runes := []rune(value)
+	if len(runes) <= r.KeepPrefix+r.KeepSuffix {
+		return strings.Repeat(string(maskChar), len(runes))
+	}
+
+	for i := r.KeepPrefix; i < len(runes)-r.KeepSuffix; i++ {
+		runes[i] = maskChar
+	}
+	return string(runes)
+}
+
+// applyEmail masks only the local part so the domain stays readable.
+func (r Rule) applyEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return r.Apply(email)
+	}
+	return r.Apply(local) + "@" + domain
+}
+
+// Masker redacts the contact details of employee responses for callers not
+// allowed to see personal data.
+type Masker struct {
+	Email          Rule
+	Phone          Rule
+	NationalNumber Rule
+}
+
+// Mask returns a redacted copy of info; info itself may be shared with the
+// cache and is never modified.
+func (m *Masker) Mask(info *models.EmployeeInfo) *models.EmployeeInfo {
+	masked := *info
+	masked.Email = m.Email.applyEmail(info.Email)
+	masked.Phone = m.Phone.Apply(info.Phone)
+	masked.NationalNumber = m.NationalNumber.Apply(info.NationalNumber)
+	return &masked
+}
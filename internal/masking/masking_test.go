@@ -0,0 +1,52 @@
+package masking
+
+import (
+	"testing"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_Apply(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     Rule
+		value    string
+		expected string
+	}{
+		{"Phone keeps both ends", Rule{Enabled: true, KeepPrefix: 3, KeepSuffix: 3}, "0791234111", "079****111"},
+		{"Short value fully masked", Rule{Enabled: true, KeepPrefix: 3, KeepSuffix: 3}, "12345", "*****"},
+		{"Disabled rule", Rule{KeepPrefix: 3, KeepSuffix: 3}, "0791234111", "0791234111"},
+		{"Empty value", Rule{Enabled: true, KeepPrefix: 1}, "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.rule.Apply(tc.value))
+		})
+	}
+}
+
+func TestMasker_Mask(t *testing.T) {
+	masker := &Masker{
+		Email:          Rule{Enabled: true, KeepPrefix: 1},
+		Phone:          Rule{Enabled: true, KeepPrefix: 3, KeepSuffix: 3},
+		NationalNumber: Rule{Enabled: true, KeepPrefix: 3, KeepSuffix: 2},
+	}
+	info := &models.EmployeeInfo{
+		ID:             1,
+		NationalNumber: "NAT1001",
+		Email:          "john@example.com",
+		Phone:          "0791234111",
+		Status:         "GREEN",
+	}
+
+	masked := masker.Mask(info)
+
+	assert.Equal(t, "j***@example.com", masked.Email)
+	assert.Equal(t, "079****111", masked.Phone)
+	assert.Equal(t, "NAT**01", masked.NationalNumber)
+	assert.Equal(t, "GREEN", masked.Status)
+	// The original, possibly cached, value is untouched
+	assert.Equal(t, "john@example.com", info.Email)
+}
@@ -1,65 +1,191 @@
 package middleware
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/auth"
 )
 
-func AuthMiddleware(secretKey string, tokenRequired bool) gin.HandlerFunc {
+// PrincipalKey is the gin context key holding the authenticated *auth.Principal.
+const PrincipalKey = "auth.principal"
+
+// Headers used by signed requests.
+const (
+	HeaderKeyID     = "X-Key-Id"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+const maxSignedBodyBytes = 1 << 20
+
+type AuthOptions struct {
+	TokenRequired bool
+	Tokens        auth.TokenVerifier
+	// Signatures enables HMAC request signing when set.
+	Signatures *auth.SignatureVerifier
+	// Certificates enables TLS client certificate authentication when set.
+	Certificates *auth.CertificateAuthenticator
+	// FailureLimit throttles failed attempts by client IP when set; callers
+	// that authenticate are not counted.
+	FailureLimit *IPLimiter
+}
+
+func AuthMiddleware(opts AuthOptions) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Skip auth if not required
-		if !tokenRequired {
+		if !opts.TokenRequired {
+			c.Set(PrincipalKey, auth.AnonymousPrincipal())
 			c.Next()
 			return
 		}
 
+		if opts.Signatures != nil && c.GetHeader(HeaderSignature) != "" {
+			authenticateSignature(c, opts.Signatures, opts.FailureLimit)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 
+		if authHeader == "" && opts.Certificates != nil {
+			if principal, err := opts.Certificates.Authenticate(c.Request.TLS); err == nil {
+				c.Set(PrincipalKey, principal)
+				c.Next()
+				return
+			}
+		}
+
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Unauthorized - Missing token",
-			})
-			c.Abort()
+			unauthorized(c, opts.FailureLimit, "Unauthorized - Missing token")
 			return
 		}
 
 		// Extract Bearer token
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Unauthorized - Invalid token format",
-			})
-			c.Abort()
+			unauthorized(c, opts.FailureLimit, "Unauthorized - Invalid token format")
 			return
 		}
 
 		token := strings.TrimPrefix(authHeader, "Bearer ")
 
-		// Validate token (simple validation for demo)
-		if !isValidToken(token, secretKey) {
+		principal, err := opts.Tokens.Verify(c.Request.Context(), token)
+		if err != nil {
+			if !errors.Is(err, auth.ErrInvalidToken) && !errors.Is(err, auth.ErrTokenExpired) {
+				_ = c.Error(err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Internal server error",
+				})
+				c.Abort()
+				return
+			}
+
+			message := "Unauthorized - Invalid token"
+			if errors.Is(err, auth.ErrTokenExpired) {
+				message = "Unauthorized - Token expired"
+			}
+			unauthorized(c, opts.FailureLimit, message)
+			return
+		}
+
+		c.Set(PrincipalKey, principal)
+		c.Next()
+	}
+}
+
+func authenticateSignature(c *gin.Context, verifier *auth.SignatureVerifier, failureLimit *IPLimiter) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSignedBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": "Request body too large",
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request format",
+			})
+		}
+		c.Abort()
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	principal, err := verifier.Verify(auth.SignedRequest{
+		KeyID:     c.GetHeader(HeaderKeyID),
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.RequestURI(),
+		Timestamp: c.GetHeader(HeaderTimestamp),
+		Nonce:     c.GetHeader(HeaderNonce),
+		Signature: c.GetHeader(HeaderSignature),
+		Body:      body,
+	})
+	if err != nil {
+		message := "Unauthorized - Invalid signature"
+		switch {
+		case errors.Is(err, auth.ErrStaleRequest):
+			message = "Unauthorized - Stale request timestamp"
+		case errors.Is(err, auth.ErrReplayedRequest):
+			message = "Unauthorized - Replayed request"
+		}
+		unauthorized(c, failureLimit, message)
+		return
+	}
+
+	c.Set(PrincipalKey, principal)
+	c.Next()
+}
+
+// unauthorized rejects a failed attempt with 401, or with 429 once the client
+// IP has failed too often.
+func unauthorized(c *gin.Context, failureLimit *IPLimiter, message string) {
+	if failureLimit != nil && !failureLimit.allow(c) {
+		return
+	}
+
+	c.JSON(http.StatusUnauthorized, gin.H{
+		"error": message,
+	})
+	c.Abort()
+}
+
+// RequireScopes rejects requests whose principal lacks any of the given scopes.
+// It must run after AuthMiddleware.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Unauthorized - Invalid token",
+				"error": "Unauthorized - Missing token",
 			})
 			c.Abort()
 			return
 		}
 
+		for _, scope := range scopes {
+			if !principal.Allows(scope) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error": "Forbidden - Missing scope " + scope,
+				})
+				c.Abort()
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
 
-func isValidToken(token, _ string) bool {
-	// Simple token validation - in production, use JWT or other secure methods
-	// For demo purposes, we accept any non-empty token
-	if token == "" {
-		return false
+// GetPrincipal returns the caller verified by AuthMiddleware, if any.
+func GetPrincipal(c *gin.Context) (*auth.Principal, bool) {
+	value, exists := c.Get(PrincipalKey)
+	if !exists {
+		return nil, false
 	}
-
-	// You can implement more sophisticated validation here:
-	// - JWT token validation
-	// - Database lookup
-	// - External auth service validation
-
-	return len(token) >= 8 // Simple length check for demo
+	principal, ok := value.(*auth.Principal)
+	return principal, ok
 }
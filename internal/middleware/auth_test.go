@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+const signatureSecret = "batch-shared-secret"
+
+func newSignatureRouter(method, path string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(method, path, AuthMiddleware(AuthOptions{
+		TokenRequired: true,
+		Signatures: auth.NewSignatureVerifier(auth.SignatureConfig{
+			Secrets: map[string]string{"batch": signatureSecret},
+			Scopes:  []string{auth.ScopeAuditRead},
+			MaxSkew: 5 * time.Minute,
+		}, auth.NewMemoryNonceStore()),
+	}), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestAuthMiddleware_SignedQuery(t *testing.T) {
+	router := newSignatureRouter(http.MethodGet, "/api/audit/access")
+
+	send := func(signedTarget, target, nonce string) int {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set(HeaderKeyID, "batch")
+		req.Header.Set(HeaderTimestamp, timestamp)
+		req.Header.Set(HeaderNonce, nonce)
+		req.Header.Set(HeaderSignature, auth.SignRequest(signatureSecret, http.MethodGet, signedTarget, timestamp, nonce, nil))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("Query string is covered", func(t *testing.T) {
+		target := "/api/audit/access?caller=batch&limit=10"
+		assert.Equal(t, http.StatusOK, send(target, target, "n-1"))
+	})
+
+	t.Run("Changed query is rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized,
+			send("/api/audit/access?caller=batch", "/api/audit/access?caller=other", "n-2"))
+	})
+
+	t.Run("Added query is rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized,
+			send("/api/audit/access", "/api/audit/access?caller=other", "n-3"))
+	})
+}
+
+func TestAuthMiddleware_SignedBody(t *testing.T) {
+	router := newSignatureRouter(http.MethodPost, "/api/GetEmpStatus")
+
+	send := func(body io.Reader) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/GetEmpStatus", body)
+		req.Header.Set(HeaderKeyID, "batch")
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+		req.Header.Set(HeaderNonce, "n-1")
+		req.Header.Set(HeaderSignature, "00")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("Oversized body", func(t *testing.T) {
+		body := bytes.NewReader(make([]byte, maxSignedBodyBytes+1))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, send(body))
+	})
+
+	t.Run("Unreadable body", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send(iotest.ErrReader(errors.New("connection reset"))))
+	})
+}
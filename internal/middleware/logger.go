@@ -17,6 +17,7 @@ func Logger(logger *logrus.Logger) gin.HandlerFunc {
 			"method":      param.Method,
 			"path":        param.Path,
 			"user_agent":  param.Request.UserAgent(),
+			"request_id":  param.Keys[RequestIDKey],
 		}).Info("HTTP Request")
 
 		return fmt.Sprintf("%s - [%s] \"%s %s %s\" %d %s \"%s\" %s\n",
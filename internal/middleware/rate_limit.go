@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/rixtrayker/getemps-service/internal/ratelimit"
+)
+
+// RateLimit enforces per-client token buckets for each route. Authenticated
+// callers are keyed by auth method and subject, so the same name from two
+// methods gets two buckets; everyone else is keyed by client IP. When the
+// store fails the request is let through.
+func RateLimit(store ratelimit.Store, policy *ratelimit.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client, tier := "ip:"+c.ClientIP(), ""
+		if principal, ok := GetPrincipal(c); ok && principal.Method != auth.MethodNone {
+			client, tier = principal.Method+":"+principal.Subject, principal.Tier
+		}
+
+		if takeToken(c, store, policy.LimitFor(c.FullPath(), tier), "client|"+c.FullPath()+"|"+client) {
+			c.Next()
+		}
+	}
+}
+
+// IPLimiter enforces per-IP token buckets for each route on requests that do
+// not authenticate, so credential guessing is throttled without callers that
+// authenticate sharing a bucket with everyone behind the same address. Tiers do
+// not apply.
+type IPLimiter struct {
+	store  ratelimit.Store
+	policy *ratelimit.Policy
+}
+
+func NewIPLimiter(store ratelimit.Store, policy *ratelimit.Policy) *IPLimiter {
+	return &IPLimiter{store: store, policy: policy}
+}
+
+// Middleware limits every request by client IP. It is meant for routes that do
+// not authenticate, such as the token endpoint; elsewhere AuthMiddleware
+// applies the limiter to failed attempts only.
+func (l *IPLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.allow(c) {
+			c.Next()
+		}
+	}
+}
+
+// allow takes a token from the client IP's bucket. When the bucket is empty it
+// responds with 429 and returns false.
+func (l *IPLimiter) allow(c *gin.Context) bool {
+	route := c.FullPath()
+	return takeToken(c, l.store, l.policy.LimitFor(route, ""), "ip|"+route+"|"+c.ClientIP())
+}
+
+// takeToken takes a token from the bucket under key and sets the rate limit
+// headers. When the bucket is empty it responds with 429 and returns false;
+// when the store fails the request is let through.
+func takeToken(c *gin.Context, store ratelimit.Store, limit ratelimit.Limit, key string) bool {
+	result, err := store.Take(c.Request.Context(), key, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return true
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": "Too many requests",
+		})
+		c.Abort()
+		return false
+	}
+
+	return true
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/rixtrayker/getemps-service/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedRouter(handlers ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handlers = append(handlers, func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/resource", handlers...)
+	return router
+}
+
+func withPrincipal(principal *auth.Principal) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(PrincipalKey, principal)
+		c.Next()
+	}
+}
+
+func get(router *gin.Engine, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/resource", nil)
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit(t *testing.T) {
+	policy := &ratelimit.Policy{Default: ratelimit.Limit{Rate: 1, Burst: 2}}
+
+	t.Run("Headers and 429 once the burst is spent", func(t *testing.T) {
+		router := newRateLimitedRouter(RateLimit(ratelimit.NewMemoryStore(), policy))
+
+		first := get(router, "10.0.0.1:1234")
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, "2", first.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "1", first.Header().Get("X-RateLimit-Remaining"))
+		assert.NotEmpty(t, first.Header().Get("X-RateLimit-Reset"))
+		assert.Empty(t, first.Header().Get("Retry-After"))
+
+		assert.Equal(t, http.StatusOK, get(router, "10.0.0.1:1234").Code)
+
+		rejected := get(router, "10.0.0.1:1234")
+		assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+		assert.Equal(t, "0", rejected.Header().Get("X-RateLimit-Remaining"))
+		assert.Equal(t, "1", rejected.Header().Get("Retry-After"))
+
+		// Other clients have their own bucket
+		assert.Equal(t, http.StatusOK, get(router, "10.0.0.2:1234").Code)
+	})
+
+	t.Run("Same subject from different auth methods", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		jwtRouter := newRateLimitedRouter(withPrincipal(&auth.Principal{Subject: "payroll", Method: auth.MethodJWT}), RateLimit(store, policy))
+		keyRouter := newRateLimitedRouter(withPrincipal(&auth.Principal{Subject: "payroll", Method: auth.MethodAPIKey}), RateLimit(store, policy))
+
+		get(jwtRouter, "10.0.0.1:1234")
+		get(jwtRouter, "10.0.0.1:1234")
+		require.Equal(t, http.StatusTooManyRequests, get(jwtRouter, "10.0.0.1:1234").Code)
+
+		assert.Equal(t, http.StatusOK, get(keyRouter, "10.0.0.1:1234").Code)
+	})
+
+	t.Run("Tier limit applies to authenticated callers", func(t *testing.T) {
+		tiered := &ratelimit.Policy{
+			Default: ratelimit.Limit{Rate: 1, Burst: 1},
+			Tiers:   map[string]ratelimit.Limit{"batch": {Rate: 1, Burst: 5}},
+		}
+		router := newRateLimitedRouter(withPrincipal(&auth.Principal{Subject: "recon", Method: auth.MethodAPIKey, Tier: "batch"}), RateLimit(ratelimit.NewMemoryStore(), tiered))
+
+		assert.Equal(t, "5", get(router, "10.0.0.1:1234").Header().Get("X-RateLimit-Limit"))
+	})
+}
+
+type stubTokenVerifier map[string]*auth.Principal
+
+func (v stubTokenVerifier) Verify(_ context.Context, token string) (*auth.Principal, error) {
+	if principal, ok := v[token]; ok {
+		return principal, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+func TestIPLimiter(t *testing.T) {
+	policy := &ratelimit.Policy{Default: ratelimit.Limit{Rate: 1, Burst: 1}}
+
+	t.Run("Routes without authentication are throttled", func(t *testing.T) {
+		router := newRateLimitedRouter(NewIPLimiter(ratelimit.NewMemoryStore(), policy).Middleware())
+
+		assert.Equal(t, http.StatusOK, get(router, "10.0.0.1:1234").Code)
+		rejected := get(router, "10.0.0.1:1234")
+		assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+		assert.Equal(t, "1", rejected.Header().Get("Retry-After"))
+	})
+
+	t.Run("Only failed authentication is counted", func(t *testing.T) {
+		router := newRateLimitedRouter(AuthMiddleware(AuthOptions{
+			TokenRequired: true,
+			Tokens:        stubTokenVerifier{"good": {Subject: "payroll", Method: auth.MethodAPIKey}},
+			FailureLimit:  NewIPLimiter(ratelimit.NewMemoryStore(), policy),
+		}))
+		send := func(token string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/api/resource", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		// Callers behind the same address do not share the failure bucket
+		for i := 0; i < 3; i++ {
+			require.Equal(t, http.StatusOK, send("good").Code)
+		}
+
+		assert.Equal(t, http.StatusUnauthorized, send("guess-1").Code)
+		rejected := send("guess-2")
+		assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+		assert.Equal(t, "1", rejected.Header().Get("Retry-After"))
+
+		assert.Equal(t, http.StatusOK, send("good").Code)
+	})
+
+	t.Run("Forwarded addresses are only believed from trusted proxies", func(t *testing.T) {
+		send := func(router *gin.Engine, forwardedFor string) int {
+			req := httptest.NewRequest(http.MethodGet, "/api/resource", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec.Code
+		}
+
+		untrusted := newRateLimitedRouter(NewIPLimiter(ratelimit.NewMemoryStore(), policy).Middleware())
+		require.NoError(t, untrusted.SetTrustedProxies(nil))
+		assert.Equal(t, http.StatusOK, send(untrusted, "203.0.113.1"))
+		assert.Equal(t, http.StatusTooManyRequests, send(untrusted, "203.0.113.2"))
+
+		trusted := newRateLimitedRouter(NewIPLimiter(ratelimit.NewMemoryStore(), policy).Middleware())
+		require.NoError(t, trusted.SetTrustedProxies([]string{"10.0.0.1"}))
+		assert.Equal(t, http.StatusOK, send(trusted, "203.0.113.1"))
+		assert.Equal(t, http.StatusOK, send(trusted, "203.0.113.2"))
+	})
+
+	t.Run("Buckets are separate from the per-client limiter", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		limit := &ratelimit.Policy{Default: ratelimit.Limit{Rate: 1, Burst: 2}}
+		router := newRateLimitedRouter(NewIPLimiter(store, limit).Middleware(), RateLimit(store, limit))
+
+		assert.Equal(t, http.StatusOK, get(router, "10.0.0.1:1234").Code)
+		assert.Equal(t, http.StatusOK, get(router, "10.0.0.1:1234").Code)
+	})
+}
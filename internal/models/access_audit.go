@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// AccessAudit records one lookup of an employee's salary data.
+type AccessAudit struct {
+	ID             int64     `json:"id" db:"id"`
+	Caller         string    `json:"caller" db:"caller"`
+	AuthMethod     string    `json:"authMethod" db:"auth_method"`
+	NationalNumber string    `json:"nationalNumber" db:"national_number"`
+	ResultCode     int       `json:"resultCode" db:"result_code"`
+	ClientIP       string    `json:"clientIp" db:"client_ip"`
+	RequestID      string    `json:"requestId" db:"request_id"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+}
+
+// AccessAuditQuery filters the audit trail. At least one of NationalNumber
+// and Caller must be set.
+type AccessAuditQuery struct {
+	NationalNumber string `form:"nationalNumber"`
+	Caller         string `form:"caller"`
+	Limit          int    `form:"limit"`
+}
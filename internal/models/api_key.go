@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type APIKey struct {
+	ID         int64      `json:"id" db:"id"`
+	KeyPrefix  string     `json:"keyPrefix" db:"key_prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Owner      string     `json:"owner" db:"owner"`
+	Scopes     string     `json:"scopes" db:"scopes"`
+	Tier       string     `json:"tier" db:"tier"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" db:"expires_at"`
+	Revoked    bool       `json:"revoked" db:"revoked"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+}
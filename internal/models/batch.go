@@ -0,0 +1,20 @@
+package models
+
+// BatchStatusRequest looks up the status of several employees at once.
+type BatchStatusRequest struct {
+	NationalNumbers []string `json:"NationalNumbers"`
+}
+
+type BatchStatusResponse struct {
+	Results []BatchStatusResult `json:"results"`
+}
+
+// BatchStatusResult is the outcome of one lookup in a batch. Code is the HTTP
+// status the lookup would have had on its own; Employee is set for 200 and
+// Error otherwise.
+type BatchStatusResult struct {
+	NationalNumber string         `json:"nationalNumber"`
+	Code           int            `json:"code"`
+	Employee       *EmployeeInfo  `json:"employee,omitempty"`
+	Error          *ErrorResponse `json:"error,omitempty"`
+}
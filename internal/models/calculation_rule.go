@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// CalculationRule is a stored rule set document. EffectiveFrom is the first
+// day of the month the rule set takes effect, or nil for the earliest rules.
+type CalculationRule struct {
+	ID            int64      `json:"id" db:"id"`
+	Version       string     `json:"version" db:"version"`
+	Definition    string     `json:"definition" db:"definition"`
+	EffectiveFrom *time.Time `json:"effectiveFrom" db:"effective_from"`
+	IsActive      bool       `json:"isActive" db:"is_active"`
+	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
+}
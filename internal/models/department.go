@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+type Department struct {
+	ID        int64     `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	ParentID  *int64    `json:"parentId,omitempty" db:"parent_id"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// StatusSummary aggregates the stored salary rankings of a group of active
+// employees.
+type StatusSummary struct {
+	Employees int `json:"employees"`
+	// StatusDistribution counts employees by status; employees missing from
+	// the ranking are counted in InsufficientData instead
+	StatusDistribution map[string]int `json:"statusDistribution"`
+	InsufficientData   int            `json:"insufficientData"`
+	// AverageSalary is the mean of the employees' adjusted average salaries
+	AverageSalary float64 `json:"averageSalary"`
+	Currency      string  `json:"currency"`
+	// RefreshedAt is when the ranking was last refreshed, absent if none of
+	// the employees are ranked
+	RefreshedAt *time.Time `json:"refreshedAt,omitempty"`
+}
+
+// DepartmentStatusSummary aggregates the statuses of a department's active
+// employees, including those of its sub-departments.
+type DepartmentStatusSummary struct {
+	Department     Department `json:"department"`
+	SubDepartments int        `json:"subDepartments"`
+	StatusSummary
+}
+
+// TeamStatusSummary aggregates the statuses of the active employees reporting
+// to a manager, directly or through other managers.
+type TeamStatusSummary struct {
+	ManagerID     int64  `json:"managerId"`
+	Manager       string `json:"manager"`
+	DirectReports int    `json:"directReports"`
+	StatusSummary
+}
+
+// DepartmentEmployee is an employee listed in a department or team view.
+type DepartmentEmployee struct {
+	ID             int64   `json:"id"`
+	Username       string  `json:"username"`
+	NationalNumber string  `json:"nationalNumber"`
+	DepartmentID   int64   `json:"departmentId,omitempty"`
+	Status         string  `json:"status"`
+	AverageSalary  float64 `json:"averageSalary"`
+	Currency       string  `json:"currency"`
+}
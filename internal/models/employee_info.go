@@ -11,11 +11,28 @@ type EmployeeInfo struct {
 	IsActive       bool          `json:"isActive"`
 	SalaryDetails  SalaryDetails `json:"salaryDetails"`
 	Status         string        `json:"status"`
-	LastUpdated    time.Time     `json:"lastUpdated"`
+	RuleVersions   []string      `json:"ruleVersions"`
+	Trend          *TrendDetails `json:"trend,omitempty"`
+	// PercentileRank is absent until the employee has been ranked
+	PercentileRank *PercentileRank `json:"percentileRank,omitempty"`
+	LastUpdated    time.Time       `json:"lastUpdated"`
+	// Explanation is only present when requested
+	Explanation *Explanation `json:"explanation,omitempty"`
 }
 
+// SalaryDetails are computed from the salaries after seasonal adjustments and
+// tax.
 type SalaryDetails struct {
-	AverageSalary float64 `json:"averageSalary"`
-	HighestSalary float64 `json:"highestSalary"`
-	SumOfSalaries float64 `json:"sumOfSalaries"`
+	AverageSalary     float64 `json:"averageSalary"`
+	HighestSalary     float64 `json:"highestSalary"`
+	SumOfSalaries     float64 `json:"sumOfSalaries"`
+	LowestSalary      float64 `json:"lowestSalary"`
+	MedianSalary      float64 `json:"medianSalary"`
+	StandardDeviation float64 `json:"standardDeviation"`
+	Percentile25      float64 `json:"p25"`
+	Percentile75      float64 `json:"p75"`
+	RecordCount       int     `json:"recordCount"`
+	FirstPeriod       *Period `json:"firstPeriod,omitempty"`
+	LastPeriod        *Period `json:"lastPeriod,omitempty"`
+	Currency          string  `json:"currency"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeRate is the value of one unit of Currency in the reporting currency
+// for a month.
+type ExchangeRate struct {
+	Currency  string          `json:"currency" db:"currency"`
+	Year      int             `json:"year" db:"year"`
+	Month     int             `json:"month" db:"month"`
+	Rate      decimal.Decimal `json:"rate" db:"rate"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// MissingExchangeRateDetails names the rate a salary could not be converted
+// without.
+type MissingExchangeRateDetails struct {
+	Currency string `json:"currency"`
+	Period   Period `json:"period"`
+}
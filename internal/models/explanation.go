@@ -0,0 +1,46 @@
+package models
+
+// Explanation traces how an employee's status was calculated. Amounts are
+// rounded to cents and, except for each month's original salary, in the
+// reporting currency. RuleVersion is the rule set that taxed the series and
+// set the status bands; each month records the rule set of its adjustments.
+type Explanation struct {
+	RuleVersion     string             `json:"ruleVersion"`
+	Currency        string             `json:"currency"`
+	Months          []MonthExplanation `json:"months"`
+	TotalBeforeTax  float64            `json:"totalBeforeTax"`
+	Tax             TaxExplanation     `json:"tax"`
+	AverageSalary   float64            `json:"averageSalary"`
+	StatusThreshold float64            `json:"statusThreshold"`
+	Comparison      string             `json:"comparison"` // e.g. "average 1384 < threshold 2000 => RED"
+}
+
+type MonthExplanation struct {
+	Year         int      `json:"year"`
+	Month        int      `json:"month"`
+	RuleVersion  string   `json:"ruleVersion"`
+	Original     float64  `json:"original"`
+	Currency     string   `json:"currency"`
+	ExchangeRate float64  `json:"exchangeRate"`
+	Converted    float64  `json:"converted"`
+	Adjustments  []string `json:"adjustments"`
+	Multiplier   float64  `json:"multiplier"`
+	Adjusted     float64  `json:"adjusted"`
+	Tax          float64  `json:"tax"`
+	Final        float64  `json:"final"`
+}
+
+type TaxExplanation struct {
+	Mode     string                `json:"mode"`
+	Basis    string                `json:"basis,omitempty"`
+	TotalTax float64               `json:"totalTax"`
+	Brackets []TaxBracketBreakdown `json:"brackets"`
+}
+
+type TaxBracketBreakdown struct {
+	From    float64 `json:"from"`
+	To      float64 `json:"to,omitempty"` // omitted for the top bracket
+	Rate    float64 `json:"rate"`
+	Taxable float64 `json:"taxable"`
+	Tax     float64 `json:"tax"`
+}
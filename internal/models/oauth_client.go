@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type OAuthClient struct {
+	ID         int64     `json:"id" db:"id"`
+	ClientID   string    `json:"clientId" db:"client_id"`
+	SecretHash string    `json:"-" db:"secret_hash"`
+	Name       string    `json:"name" db:"name"`
+	Scopes     string    `json:"scopes" db:"scopes"`
+	Tier       string    `json:"tier" db:"tier"`
+	IsActive   bool      `json:"isActive" db:"is_active"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
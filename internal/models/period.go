@@ -0,0 +1,30 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period is a calendar month.
+type Period struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+}
+
+func PeriodOf(t time.Time) Period {
+	return Period{Year: t.Year(), Month: int(t.Month())}
+}
+
+// AddMonths returns the period n months later, or earlier for negative n.
+func (p Period) AddMonths(n int) Period {
+	index := p.Year*12 + (p.Month - 1) + n
+	return Period{Year: index / 12, Month: index%12 + 1}
+}
+
+func (p Period) Before(other Period) bool {
+	return p.Year < other.Year || (p.Year == other.Year && p.Month < other.Month)
+}
+
+func (p Period) String() string {
+	return fmt.Sprintf("%04d-%02d", p.Year, p.Month)
+}
@@ -2,8 +2,55 @@ package models
 
 type EmployeeRequest struct {
 	NationalNumber string `json:"NationalNumber"`
+	// Explain adds a per-month calculation breakdown to the response
+	Explain bool `json:"explain"`
+	// Optional calculation window: a range of months, inclusive, or the
+	// trailing lastMonths including the current month
+	FromYear   int `json:"fromYear"`
+	FromMonth  int `json:"fromMonth"`
+	ToYear     int `json:"toYear"`
+	ToMonth    int `json:"toMonth"`
+	LastMonths int `json:"lastMonths"`
 }
 
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error   string      `json:"error"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+type CreateAPIKeyRequest struct {
+	Owner    string   `json:"owner"`
+	Scopes   []string `json:"scopes"`
+	Tier     string   `json:"tier"`
+	TTLHours int      `json:"ttlHours"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey APIKey `json:"apiKey"`
+	Key    string `json:"key"`
+}
+
+type CreateOAuthClientRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	Tier   string   `json:"tier"`
+}
+
+type CreateOAuthClientResponse struct {
+	Client       OAuthClient `json:"client"`
+	ClientSecret string      `json:"clientSecret"`
+}
+
+// TokenResponse is the RFC 6749 access token response.
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// OAuthErrorResponse is the RFC 6749 error response used by the token endpoint.
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
 }
@@ -1,12 +1,17 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type Salary struct {
-	ID        int64     `json:"id" db:"id"`
-	Year      int       `json:"year" db:"year"`
-	Month     int       `json:"month" db:"month"`
-	Salary    float64   `json:"salary" db:"salary"`
-	UserID    int64     `json:"userId" db:"user_id"`
-	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	ID        int64           `json:"id" db:"id"`
+	Year      int             `json:"year" db:"year"`
+	Month     int             `json:"month" db:"month"`
+	Salary    decimal.Decimal `json:"salary" db:"salary"`
+	Currency  string          `json:"currency" db:"currency"`
+	UserID    int64           `json:"userId" db:"user_id"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
 }
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SalaryRanking is an active employee's adjusted average salary and its
+// percentiles as of the last ranking refresh.
+type SalaryRanking struct {
+	UserID        int64           `db:"user_id"`
+	DepartmentID  *int64          `db:"department_id"`
+	Status        string          `db:"status"`
+	AverageSalary decimal.Decimal `db:"average_salary"`
+	Currency      string          `db:"currency"`
+	PercentileRank
+}
+
+// PercentileRank places an employee's adjusted average salary among active
+// employees, from 0 for the lowest to 100 for the highest. Department only
+// compares employees of the same department and is absent without one.
+type PercentileRank struct {
+	Overall             float64   `json:"overall" db:"overall_percentile"`
+	Department          *float64  `json:"department,omitempty" db:"department_percentile"`
+	Employees           int       `json:"employees" db:"employees"`
+	DepartmentEmployees int       `json:"departmentEmployees,omitempty" db:"department_employees"`
+	RefreshedAt         time.Time `json:"refreshedAt" db:"refreshed_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// SimulateStatusRequest is a hypothetical salary series, in the reporting
+// currency, to calculate a status for.
+type SimulateStatusRequest struct {
+	Salaries []SimulatedSalary `json:"salaries"`
+	Explain  bool              `json:"explain"`
+}
+
+type SimulatedSalary struct {
+	Year   int             `json:"year"`
+	Month  int             `json:"month"`
+	Salary decimal.Decimal `json:"salary"`
+}
+
+type SimulateStatusResponse struct {
+	SalaryDetails SalaryDetails `json:"salaryDetails"`
+	Status        string        `json:"status"`
+	RuleVersions  []string      `json:"ruleVersions"`
+	Trend         *TrendDetails `json:"trend,omitempty"`
+	// Explanation is only present when requested
+	Explanation *Explanation `json:"explanation,omitempty"`
+}
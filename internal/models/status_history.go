@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// StatusSnapshot records a computed status so changes can be traced over time.
+type StatusSnapshot struct {
+	ID            int64     `json:"id" db:"id"`
+	UserID        int64     `json:"-" db:"user_id"`
+	Status        string    `json:"status" db:"status"`
+	AverageSalary float64   `json:"averageSalary" db:"average_salary"`
+	HighestSalary float64   `json:"highestSalary" db:"highest_salary"`
+	SumOfSalaries float64   `json:"sumOfSalaries" db:"sum_of_salaries"`
+	Currency      string    `json:"currency" db:"currency"`
+	RuleVersion   string    `json:"ruleVersion" db:"rule_version"`
+	ComputedAt    time.Time `json:"computedAt" db:"computed_at"`
+}
+
+type StatusHistoryQuery struct {
+	Limit int `form:"limit"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// InsufficientDataDetails explains why salary data was refused.
+type InsufficientDataDetails struct {
+	Rule   string            `json:"rule"`
+	Reason string            `json:"reason"`
+	Data   SalaryDataSummary `json:"data"`
+}
+
+// SalaryDataSummary describes the salary records that were considered.
+// Periods are omitted when only the record count is known.
+type SalaryDataSummary struct {
+	Records           int     `json:"records"`
+	First             *Period `json:"first,omitempty"`
+	Latest            *Period `json:"latest,omitempty"`
+	LargestGapMonths  int     `json:"largestGapMonths"`
+	MonthsSinceLatest int     `json:"monthsSinceLatest"`
+}
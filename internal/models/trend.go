@@ -0,0 +1,10 @@
+package models
+
+// TrendDetails describe how an employee's salaries are moving. Growth and
+// volatility are fractions, e.g. 0.012 is 1.2% per month.
+type TrendDetails struct {
+	Slope                float64 `json:"slope"`
+	MonthOverMonthGrowth float64 `json:"monthOverMonthGrowth"`
+	Volatility           float64 `json:"volatility"`
+	Classification       string  `json:"classification,omitempty"` // RISING, STABLE or FALLING
+}
@@ -9,6 +9,8 @@ type User struct {
 	Email          string    `json:"email" db:"email"`
 	Phone          string    `json:"phone" db:"phone"`
 	IsActive       bool      `json:"isActive" db:"is_active"`
+	DepartmentID   *int64    `json:"departmentId,omitempty" db:"department_id"`
+	ManagerID      *int64    `json:"managerId,omitempty" db:"manager_id"`
 	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
 	UpdatedAt      time.Time `json:"updatedAt" db:"updated_at"`
 }
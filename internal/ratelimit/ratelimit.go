@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket refilled at Rate tokens per second holding at most
+// Burst tokens.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result describes the bucket after a Take.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next request would be allowed.
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again.
+	ResetAfter time.Duration
+}
+
+// Store takes tokens from buckets identified by key. Implementations backed
+// by shared storage let several replicas enforce the same limits.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+const sweepInterval = time.Minute
+
+func (s *memoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+
+	// Refill for the time elapsed since the last request
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.updated = now
+	b.limit = limit
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+
+	result.Remaining = int(math.Floor(b.tokens))
+	result.ResetAfter = secondsToDuration((float64(limit.Burst) - b.tokens) / limit.Rate)
+
+	return result, nil
+}
+
+// sweep drops buckets that have been idle long enough to be full again,
+// since a fresh bucket is equivalent.
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		refill := secondsToDuration((float64(b.limit.Burst) - b.tokens) / b.limit.Rate)
+		if now.Sub(b.updated) >= refill {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
+
+// Policy selects the limit for a request. A tier limit takes precedence over
+// a route limit, which takes precedence over the default.
+type Policy struct {
+	Default Limit
+	Routes  map[string]Limit // route pattern -> limit
+	Tiers   map[string]Limit // client tier -> limit
+}
+
+func (p *Policy) LimitFor(route, tier string) Limit {
+	if limit, ok := p.Tiers[tier]; ok && tier != "" {
+		return limit
+	}
+	if limit, ok := p.Routes[route]; ok {
+		return limit
+	}
+	return p.Default
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 2}
+
+	newStore := func(now *time.Time) *memoryStore {
+		store := NewMemoryStore().(*memoryStore)
+		store.now = func() time.Time { return *now }
+		return store
+	}
+
+	t.Run("Burst then reject with retry after", func(t *testing.T) {
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		store := newStore(&now)
+
+		for i := 0; i < 2; i++ {
+			result, err := store.Take(ctx, "client", limit)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+		}
+
+		result, err := store.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Equal(t, time.Second, result.RetryAfter)
+		assert.Equal(t, 2*time.Second, result.ResetAfter)
+	})
+
+	t.Run("Tokens refill over time", func(t *testing.T) {
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		store := newStore(&now)
+
+		store.Take(ctx, "client", limit)
+		store.Take(ctx, "client", limit)
+
+		now = now.Add(time.Second)
+		result, err := store.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+
+	t.Run("Clients have separate buckets", func(t *testing.T) {
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		store := newStore(&now)
+
+		store.Take(ctx, "batch", limit)
+		store.Take(ctx, "batch", limit)
+
+		result, err := store.Take(ctx, "portal", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+}
+
+func TestPolicy_LimitFor(t *testing.T) {
+	policy := &Policy{
+		Default: Limit{Rate: 10, Burst: 20},
+		Routes:  map[string]Limit{"/api/auth/token": {Rate: 1, Burst: 5}},
+		Tiers:   map[string]Limit{"batch": {Rate: 2, Burst: 5}},
+	}
+
+	assert.Equal(t, policy.Default, policy.LimitFor("/api/GetEmpStatus", ""))
+	assert.Equal(t, Limit{Rate: 1, Burst: 5}, policy.LimitFor("/api/auth/token", ""))
+	assert.Equal(t, Limit{Rate: 2, Burst: 5}, policy.LimitFor("/api/GetEmpStatus", "batch"))
+	assert.Equal(t, policy.Default, policy.LimitFor("/api/GetEmpStatus", "unknown"))
+}
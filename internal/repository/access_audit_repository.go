@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+type accessAuditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAccessAuditRepository(db *sqlx.DB) AccessAuditRepository {
+	return &accessAuditRepository{db: db}
+}
+
+func (r *accessAuditRepository) Create(ctx context.Context, entry *models.AccessAudit) error {
+	query := `
+		INSERT INTO access_audit (caller, auth_method, national_number, result_code, client_ip, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query,
+		entry.Caller, entry.AuthMethod, entry.NationalNumber, entry.ResultCode, entry.ClientIP, entry.RequestID,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record access audit: %w", err)
+	}
+
+	return nil
+}
+
+func (r *accessAuditRepository) List(ctx context.Context, query models.AccessAuditQuery) ([]models.AccessAudit, error) {
+	var conditions []string
+	var args []interface{}
+
+	if query.NationalNumber != "" {
+		args = append(args, query.NationalNumber)
+		conditions = append(conditions, fmt.Sprintf("national_number = $%d", len(args)))
+	}
+	if query.Caller != "" {
+		// Callers are indexed by hash since subjects have no length limit
+		args = append(args, query.Caller)
+		conditions = append(conditions, fmt.Sprintf("md5(caller) = md5($%d) AND caller = $%d", len(args), len(args)))
+	}
+	args = append(args, query.Limit)
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT id, caller, auth_method, national_number, result_code, client_ip, request_id, created_at
+		FROM access_audit
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args))
+
+	var entries []models.AccessAudit
+	err := r.db.SelectContext(ctx, &entries, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access audit: %w", err)
+	}
+
+	return entries, nil
+}
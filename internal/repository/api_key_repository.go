@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+type apiKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (key_prefix, key_hash, owner, scopes, tier, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, key.KeyPrefix, key.KeyHash, key.Owner, key.Scopes, key.Tier, key.ExpiresAt).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api key for %s: %w", key.Owner, err)
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := `
+		SELECT id, key_prefix, key_hash, owner, scopes, tier, expires_at, revoked, revoked_at, last_used_at, created_at
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+
+	var key models.APIKey
+	err := r.db.GetContext(ctx, &key, query, keyHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("api key %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get api key by hash: %w", err)
+	}
+
+	return &key, nil
+}
+
+func (r *apiKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	query := `
+		SELECT id, key_prefix, key_hash, owner, scopes, tier, expires_at, revoked, revoked_at, last_used_at, created_at
+		FROM api_keys
+		ORDER BY id ASC
+	`
+
+	var keys []models.APIKey
+	err := r.db.SelectContext(ctx, &keys, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id int64) error {
+	query := `
+		UPDATE api_keys
+		SET revoked = TRUE, revoked_at = NOW()
+		WHERE id = $1 AND revoked = FALSE
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key %d: %w", id, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key %d: %w", id, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("active api key %d %w", id, ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = $2
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update last use of api key %d: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+type calculationRuleRepository struct {
+	db *sqlx.DB
+}
+
+func NewCalculationRuleRepository(db *sqlx.DB) CalculationRuleRepository {
+	return &calculationRuleRepository{db: db}
+}
+
+func (r *calculationRuleRepository) ListActive(ctx context.Context) ([]models.CalculationRule, error) {
+	query := `
+		SELECT id, version, definition, effective_from, is_active, created_at
+		FROM calculation_rules
+		WHERE is_active = TRUE
+		ORDER BY effective_from ASC NULLS FIRST
+	`
+
+	var stored []models.CalculationRule
+	err := r.db.SelectContext(ctx, &stored, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calculation rules: %w", err)
+	}
+	if len(stored) == 0 {
+		return nil, fmt.Errorf("active calculation rules %w", ErrNotFound)
+	}
+
+	return stored, nil
+}
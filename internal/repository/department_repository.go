@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+type departmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewDepartmentRepository(db *sqlx.DB) DepartmentRepository {
+	return &departmentRepository{db: db}
+}
+
+func (r *departmentRepository) ListSubtree(ctx context.Context, departmentID int64) ([]models.Department, error) {
+	// UNION rather than UNION ALL stops at departments already visited
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id, name, parent_id, created_at
+			FROM departments
+			WHERE id = $1
+			UNION
+			SELECT d.id, d.name, d.parent_id, d.created_at
+			FROM departments d
+			JOIN subtree s ON d.parent_id = s.id
+		)
+		SELECT id, name, parent_id, created_at
+		FROM subtree
+		ORDER BY id = $1 DESC, id ASC
+	`
+
+	var departments []models.Department
+	err := r.db.SelectContext(ctx, &departments, query, departmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get departments under %d: %w", departmentID, err)
+	}
+	if len(departments) == 0 {
+		return nil, fmt.Errorf("department %d %w", departmentID, ErrNotFound)
+	}
+
+	return departments, nil
+}
+
+func (r *departmentRepository) ListActiveMembers(ctx context.Context, departmentIDs []int64) ([]models.User, error) {
+	query := `
+		SELECT id, username, national_number, email, phone, is_active, department_id, manager_id, created_at, updated_at
+		FROM users
+		WHERE department_id = ANY($1)
+		  AND is_active = TRUE
+		ORDER BY id ASC
+	`
+
+	var users []models.User
+	err := r.db.SelectContext(ctx, &users, query, pq.Array(departmentIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get department members: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *departmentRepository) ListActiveReports(ctx context.Context, managerID int64) ([]models.User, error) {
+	// Inactive managers are walked through so their reports stay in the team;
+	// UNION stops at users already visited
+	query := `
+		WITH RECURSIVE reports AS (
+			SELECT id
+			FROM users
+			WHERE manager_id = $1
+			UNION
+			SELECT u.id
+			FROM users u
+			JOIN reports r ON u.manager_id = r.id
+		)
+		SELECT id, username, national_number, email, phone, is_active, department_id, manager_id, created_at, updated_at
+		FROM users
+		WHERE id IN (SELECT id FROM reports)
+		  AND id <> $1
+		  AND is_active = TRUE
+		ORDER BY id ASC
+	`
+
+	var users []models.User
+	err := r.db.SelectContext(ctx, &users, query, managerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reports of user %d: %w", managerID, err)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+type exchangeRateRepository struct {
+	db *sqlx.DB
+}
+
+func NewExchangeRateRepository(db *sqlx.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) GetRates(ctx context.Context, currencies []string, from, to models.Period) ([]models.ExchangeRate, error) {
+	query := `
+		SELECT currency, year, month, rate, created_at
+		FROM exchange_rates
+		WHERE currency = ANY($1)
+		  AND (year, month) >= ($2, $3)
+		  AND (year, month) <= ($4, $5)
+		ORDER BY currency, year, month
+	`
+
+	var rates []models.ExchangeRate
+	err := r.db.SelectContext(ctx, &rates, query, pq.Array(currencies), from.Year, from.Month, to.Year, to.Month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rates from %s to %s: %w", from, to, err)
+	}
+
+	return rates, nil
+}
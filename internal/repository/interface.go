@@ -2,15 +2,82 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/rixtrayker/getemps-service/internal/models"
 )
 
+// ErrNotFound is wrapped by repository errors for missing records.
+var ErrNotFound = errors.New("not found")
+
 type UserRepository interface {
 	GetByNationalNumber(ctx context.Context, nationalNumber string) (*models.User, error)
+	ListActive(ctx context.Context) ([]models.User, error)
 }
 
 type SalaryRepository interface {
 	GetByUserID(ctx context.Context, userID int64) ([]models.Salary, error)
 	CountByUserID(ctx context.Context, userID int64) (int, error)
+	// Range variants only consider salaries from one period to another, inclusive
+	GetByUserIDInRange(ctx context.Context, userID int64, from, to models.Period) ([]models.Salary, error)
+	CountByUserIDInRange(ctx context.Context, userID int64, from, to models.Period) (int, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	List(ctx context.Context) ([]models.APIKey, error)
+	Revoke(ctx context.Context, id int64) error
+	TouchLastUsed(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *models.OAuthClient) error
+	GetByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error)
+	List(ctx context.Context) ([]models.OAuthClient, error)
+	Deactivate(ctx context.Context, clientID string) error
+}
+
+type AccessAuditRepository interface {
+	Create(ctx context.Context, entry *models.AccessAudit) error
+	List(ctx context.Context, query models.AccessAuditQuery) ([]models.AccessAudit, error)
+}
+
+type CalculationRuleRepository interface {
+	// ListActive returns the active rule sets by effective date
+	ListActive(ctx context.Context) ([]models.CalculationRule, error)
+}
+
+type StatusHistoryRepository interface {
+	Create(ctx context.Context, snapshot *models.StatusSnapshot) error
+	// ListByUserID returns the newest snapshots first
+	ListByUserID(ctx context.Context, userID int64, limit int) ([]models.StatusSnapshot, error)
+}
+
+type DepartmentRepository interface {
+	// ListSubtree returns a department followed by all departments below it
+	ListSubtree(ctx context.Context, departmentID int64) ([]models.Department, error)
+	// ListActiveMembers returns the active users of the given departments
+	ListActiveMembers(ctx context.Context, departmentIDs []int64) ([]models.User, error)
+	// ListActiveReports returns the active users reporting to a manager,
+	// directly or through other managers
+	ListActiveReports(ctx context.Context, managerID int64) ([]models.User, error)
+}
+
+type SalaryRankingRepository interface {
+	// Replace swaps the stored ranking for a freshly computed one
+	Replace(ctx context.Context, rankings []models.SalaryRanking) error
+	// GetPercentileRank returns an employee's percentiles from the stored
+	// ranking
+	GetPercentileRank(ctx context.Context, userID int64) (*models.PercentileRank, error)
+	// ListByUserIDs returns the stored rankings of the given employees; those
+	// not ranked are left out
+	ListByUserIDs(ctx context.Context, userIDs []int64) ([]models.SalaryRanking, error)
+}
+
+type ExchangeRateRepository interface {
+	// GetRates returns the rates of the given currencies from one period to
+	// another, inclusive
+	GetRates(ctx context.Context, currencies []string, from, to models.Period) ([]models.ExchangeRate, error)
 }
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+type oauthClientRepository struct {
+	db *sqlx.DB
+}
+
+func NewOAuthClientRepository(db *sqlx.DB) OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+func (r *oauthClientRepository) Create(ctx context.Context, client *models.OAuthClient) error {
+	query := `
+		INSERT INTO oauth_clients (client_id, secret_hash, name, scopes, tier)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, is_active, created_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, client.ClientID, client.SecretHash, client.Name, client.Scopes, client.Tier).
+		Scan(&client.ID, &client.IsActive, &client.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create oauth client %s: %w", client.Name, err)
+	}
+
+	return nil
+}
+
+func (r *oauthClientRepository) GetByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+	query := `
+		SELECT id, client_id, secret_hash, name, scopes, tier, is_active, created_at
+		FROM oauth_clients
+		WHERE client_id = $1
+	`
+
+	var client models.OAuthClient
+	err := r.db.GetContext(ctx, &client, query, clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("oauth client %s %w", clientID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get oauth client: %w", err)
+	}
+
+	return &client, nil
+}
+
+func (r *oauthClientRepository) List(ctx context.Context) ([]models.OAuthClient, error) {
+	query := `
+		SELECT id, client_id, secret_hash, name, scopes, tier, is_active, created_at
+		FROM oauth_clients
+		ORDER BY id ASC
+	`
+
+	var clients []models.OAuthClient
+	err := r.db.SelectContext(ctx, &clients, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth clients: %w", err)
+	}
+
+	return clients, nil
+}
+
+func (r *oauthClientRepository) Deactivate(ctx context.Context, clientID string) error {
+	query := `
+		UPDATE oauth_clients
+		SET is_active = FALSE
+		WHERE client_id = $1 AND is_active = TRUE
+	`
+
+	result, err := r.db.ExecContext(ctx, query, clientID)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate oauth client %s: %w", clientID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to deactivate oauth client %s: %w", clientID, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("active oauth client %s %w", clientID, ErrNotFound)
+	}
+
+	return nil
+}
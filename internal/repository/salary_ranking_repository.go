@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+type salaryRankingRepository struct {
+	db *sqlx.DB
+}
+
+func NewSalaryRankingRepository(db *sqlx.DB) SalaryRankingRepository {
+	return &salaryRankingRepository{db: db}
+}
+
+func (r *salaryRankingRepository) Replace(ctx context.Context, rankings []models.SalaryRanking) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin ranking refresh: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM salary_rankings`); err != nil {
+		return fmt.Errorf("failed to clear salary rankings: %w", err)
+	}
+
+	insert, err := tx.PreparexContext(ctx, `
+		INSERT INTO salary_rankings
+			(user_id, department_id, status, average_salary, currency,
+			 overall_percentile, department_percentile, employees, department_employees, refreshed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare salary ranking insert: %w", err)
+	}
+	defer insert.Close()
+
+	for _, ranking := range rankings {
+		if _, err = insert.ExecContext(ctx,
+			ranking.UserID, ranking.DepartmentID, ranking.Status, ranking.AverageSalary, ranking.Currency,
+			ranking.Overall, ranking.Department, ranking.Employees, ranking.DepartmentEmployees, ranking.RefreshedAt,
+		); err != nil {
+			return fmt.Errorf("failed to store salary ranking for user %d: %w", ranking.UserID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit salary rankings: %w", err)
+	}
+	return nil
+}
+
+func (r *salaryRankingRepository) GetPercentileRank(ctx context.Context, userID int64) (*models.PercentileRank, error) {
+	query := `
+		SELECT overall_percentile, department_percentile, employees, department_employees, refreshed_at
+		FROM salary_rankings
+		WHERE user_id = $1
+	`
+
+	var rank models.PercentileRank
+	err := r.db.GetContext(ctx, &rank, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("salary ranking for user %d %w", userID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get percentile rank for user %d: %w", userID, err)
+	}
+
+	return &rank, nil
+}
+
+func (r *salaryRankingRepository) ListByUserIDs(ctx context.Context, userIDs []int64) ([]models.SalaryRanking, error) {
+	query := `
+		SELECT user_id, department_id, status, average_salary, currency,
+		       overall_percentile, department_percentile, employees, department_employees, refreshed_at
+		FROM salary_rankings
+		WHERE user_id = ANY($1)
+		ORDER BY user_id ASC
+	`
+
+	var rankings []models.SalaryRanking
+	err := r.db.SelectContext(ctx, &rankings, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get salary rankings: %w", err)
+	}
+
+	return rankings, nil
+}
@@ -18,7 +18,7 @@ func NewSalaryRepository(db *sqlx.DB) SalaryRepository {
 
 func (r *salaryRepository) GetByUserID(ctx context.Context, userID int64) ([]models.Salary, error) {
 	query := `
-		SELECT id, year, month, salary, user_id, created_at
+		SELECT id, year, month, salary, currency, user_id, created_at
 		FROM salaries
 		WHERE user_id = $1
 		ORDER BY year ASC, month ASC
@@ -48,3 +48,40 @@ func (r *salaryRepository) CountByUserID(ctx context.Context, userID int64) (int
 
 	return count, nil
 }
+
+func (r *salaryRepository) GetByUserIDInRange(ctx context.Context, userID int64, from, to models.Period) ([]models.Salary, error) {
+	query := `
+		SELECT id, year, month, salary, currency, user_id, created_at
+		FROM salaries
+		WHERE user_id = $1
+		  AND (year, month) >= ($2, $3)
+		  AND (year, month) <= ($4, $5)
+		ORDER BY year ASC, month ASC
+	`
+
+	var salaries []models.Salary
+	err := r.db.SelectContext(ctx, &salaries, query, userID, from.Year, from.Month, to.Year, to.Month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get salaries for user %d from %s to %s: %w", userID, from, to, err)
+	}
+
+	return salaries, nil
+}
+
+func (r *salaryRepository) CountByUserIDInRange(ctx context.Context, userID int64, from, to models.Period) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM salaries
+		WHERE user_id = $1
+		  AND (year, month) >= ($2, $3)
+		  AND (year, month) <= ($4, $5)
+	`
+
+	var count int
+	err := r.db.GetContext(ctx, &count, query, userID, from.Year, from.Month, to.Year, to.Month)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count salaries for user %d from %s to %s: %w", userID, from, to, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+type statusHistoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewStatusHistoryRepository(db *sqlx.DB) StatusHistoryRepository {
+	return &statusHistoryRepository{db: db}
+}
+
+func (r *statusHistoryRepository) Create(ctx context.Context, snapshot *models.StatusSnapshot) error {
+	query := `
+		INSERT INTO employee_status_history
+			(user_id, status, average_salary, highest_salary, sum_of_salaries, currency, rule_version, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowxContext(ctx, query,
+		snapshot.UserID, snapshot.Status, snapshot.AverageSalary, snapshot.HighestSalary, snapshot.SumOfSalaries,
+		snapshot.Currency, snapshot.RuleVersion, snapshot.ComputedAt,
+	).Scan(&snapshot.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record status history: %w", err)
+	}
+
+	return nil
+}
+
+func (r *statusHistoryRepository) ListByUserID(ctx context.Context, userID int64, limit int) ([]models.StatusSnapshot, error) {
+	query := `
+		SELECT id, user_id, status, average_salary, highest_salary, sum_of_salaries, currency, rule_version, computed_at
+		FROM employee_status_history
+		WHERE user_id = $1
+		ORDER BY computed_at DESC, id DESC
+		LIMIT $2
+	`
+
+	var snapshots []models.StatusSnapshot
+	err := r.db.SelectContext(ctx, &snapshots, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history for user %d: %w", userID, err)
+	}
+
+	return snapshots, nil
+}
@@ -19,7 +19,7 @@ func NewUserRepository(db *sqlx.DB) UserRepository {
 
 func (r *userRepository) GetByNationalNumber(ctx context.Context, nationalNumber string) (*models.User, error) {
 	query := `
-		SELECT id, username, national_number, email, phone, is_active, created_at, updated_at
+		SELECT id, username, national_number, email, phone, is_active, department_id, manager_id, created_at, updated_at
 		FROM users
 		WHERE national_number = $1
 	`
@@ -35,3 +35,20 @@ func (r *userRepository) GetByNationalNumber(ctx context.Context, nationalNumber
 
 	return &user, nil
 }
+
+func (r *userRepository) ListActive(ctx context.Context) ([]models.User, error) {
+	query := `
+		SELECT id, username, national_number, email, phone, is_active, department_id, manager_id, created_at, updated_at
+		FROM users
+		WHERE is_active = TRUE
+		ORDER BY id ASC
+	`
+
+	var users []models.User
+	err := r.db.SelectContext(ctx, &users, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active users: %w", err)
+	}
+
+	return users, nil
+}
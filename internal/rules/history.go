@@ -0,0 +1,160 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// effectiveLayout is the format of EffectiveFrom: rule sets take effect from
+// the start of a month.
+const effectiveLayout = "2006-01"
+
+// History is a sequence of rule sets, each in force from its EffectiveFrom
+// month until the next one takes over. The earliest rule set also covers the
+// months before it, so every month has rules.
+type History struct {
+	sets []*RuleSet
+	// from holds each set's first month as year*12 + month - 1
+	from []int
+}
+
+// SingleHistory is a history of one rule set in force for every month,
+// whatever its effective date.
+func SingleHistory(set *RuleSet) *History {
+	return &History{sets: []*RuleSet{set}, from: []int{-1}}
+}
+
+// NewHistory orders rule sets by effective date. At most one rule set may
+// omit EffectiveFrom; it is the earliest.
+func NewHistory(sets ...*RuleSet) (*History, error) {
+	if len(sets) == 0 {
+		return nil, errors.New("at least one rule set is required")
+	}
+
+	type entry struct {
+		set  *RuleSet
+		from int
+	}
+	entries := make([]entry, len(sets))
+	versions := make(map[string]bool, len(sets))
+	for i, set := range sets {
+		if versions[set.Version] {
+			return nil, fmt.Errorf("duplicate rule set version %q", set.Version)
+		}
+		versions[set.Version] = true
+
+		from, err := set.effectiveMonth()
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry{set: set, from: from}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].from < entries[j].from })
+
+	history := &History{
+		sets: make([]*RuleSet, len(entries)),
+		from: make([]int, len(entries)),
+	}
+	for i, e := range entries {
+		if i > 0 && e.from == entries[i-1].from {
+			return nil, fmt.Errorf("rule sets %q and %q take effect in the same month", entries[i-1].set.Version, e.set.Version)
+		}
+		history.sets[i] = e.set
+		history.from[i] = e.from
+	}
+
+	return history, nil
+}
+
+// At returns the rule set in force for a month.
+func (h *History) At(year, month int) *RuleSet {
+	index := year*12 + month - 1
+	i := sort.Search(len(h.from), func(i int) bool { return h.from[i] > index })
+	if i == 0 {
+		return h.sets[0]
+	}
+	return h.sets[i-1]
+}
+
+// Latest returns the rule set that took effect last.
+func (h *History) Latest() *RuleSet {
+	return h.sets[len(h.sets)-1]
+}
+
+// Versions lists the rule set versions in effective order.
+func (h *History) Versions() []string {
+	versions := make([]string, len(h.sets))
+	for i, set := range h.sets {
+		versions[i] = set.Version
+	}
+	return versions
+}
+
+// effectiveMonth returns the first month the rule set is in force, or -1
+// when it has no effective date.
+func (r *RuleSet) effectiveMonth() (int, error) {
+	if r.EffectiveFrom == "" {
+		return -1, nil
+	}
+	t, err := time.Parse(effectiveLayout, r.EffectiveFrom)
+	if err != nil {
+		return 0, fmt.Errorf("rule set %q: effectiveFrom must be YYYY-MM, got %q", r.Version, r.EffectiveFrom)
+	}
+	return t.Year()*12 + int(t.Month()) - 1, nil
+}
+
+// historyDocument is a rule file holding either one rule set or a list of
+// versioned rule sets under ruleSets.
+type historyDocument struct {
+	RuleSet  `yaml:",inline"`
+	RuleSets []*RuleSet `json:"ruleSets" yaml:"ruleSets"`
+}
+
+// ParseHistory decodes and validates a rule file of one or more rule sets.
+// format is "json" or "yaml".
+func ParseHistory(data []byte, format string) (*History, error) {
+	var doc historyDocument
+	var err error
+
+	switch format {
+	case "json":
+		err = json.Unmarshal(data, &doc)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("unsupported rule format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+
+	sets := doc.RuleSets
+	if len(sets) == 0 {
+		sets = []*RuleSet{&doc.RuleSet}
+	}
+	for _, set := range sets {
+		if err := set.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rules: %w", err)
+		}
+	}
+
+	return NewHistory(sets...)
+}
+
+// LoadHistoryFile reads a JSON or YAML rule file, chosen by extension.
+func LoadHistoryFile(path string) (*History, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	return ParseHistory(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func versioned(version, effectiveFrom string, threshold float64) *RuleSet {
+	ruleSet := Default()
+	ruleSet.Version = version
+	ruleSet.EffectiveFrom = effectiveFrom
+	ruleSet.Status.Threshold = threshold
+	return ruleSet
+}
+
+func TestHistory_At(t *testing.T) {
+	history, err := NewHistory(
+		versioned("2025", "2025-01", 2200),
+		versioned("original", "", 2000),
+		versioned("2024-h2", "2024-07", 2100),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"original", "2024-h2", "2025"}, history.Versions())
+	assert.Equal(t, "2025", history.Latest().Version)
+
+	testCases := []struct {
+		year, month int
+		expected    string
+	}{
+		{2019, 3, "original"},
+		{2024, 6, "original"},
+		{2024, 7, "2024-h2"},
+		{2024, 12, "2024-h2"},
+		{2025, 1, "2025"},
+		{2030, 8, "2025"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, history.At(tc.year, tc.month).Version, "%d-%02d", tc.year, tc.month)
+	}
+}
+
+func TestNewHistory_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		sets []*RuleSet
+	}{
+		{"No rule sets", nil},
+		{"Duplicate version", []*RuleSet{versioned("v1", "", 2000), versioned("v1", "2025-01", 2000)}},
+		{"Same effective month", []*RuleSet{versioned("v1", "2025-01", 2000), versioned("v2", "2025-01", 2100)}},
+		{"Two undated rule sets", []*RuleSet{versioned("v1", "", 2000), versioned("v2", "", 2100)}},
+		{"Malformed effective date", []*RuleSet{versioned("v1", "2025-01-01", 2000)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewHistory(tc.sets...)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseHistory(t *testing.T) {
+	t.Run("Versioned rule sets", func(t *testing.T) {
+		document := `
+ruleSets:
+  - version: "2025"
+    effectiveFrom: "2025-01"
+    tax: {threshold: 12000, rate: 0.08}
+    status: {threshold: 2200}
+  - version: original
+    tax: {threshold: 10000, rate: 0.07}
+    status: {threshold: 2000}
+`
+		history, err := ParseHistory([]byte(document), "yaml")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"original", "2025"}, history.Versions())
+		assert.Equal(t, 2200.0, history.At(2025, 3).Status.Threshold)
+	})
+
+	t.Run("Single rule set", func(t *testing.T) {
+		history, err := ParseHistory([]byte(`{"version": "v2", "tax": {"threshold": 12000, "rate": 0.1}, "status": {"threshold": 2500}}`), "json")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v2"}, history.Versions())
+	})
+
+	t.Run("Invalid rule set", func(t *testing.T) {
+		_, err := ParseHistory([]byte(`{"ruleSets": [{"version": "v2", "status": {"threshold": 0}}]}`), "json")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,254 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleSet holds the salary calculation policy. Seasonal adjustments are
+// applied in order, then the tax rule, then the status threshold.
+// EffectiveFrom (YYYY-MM) is the first month a versioned rule set is in force.
+type RuleSet struct {
+	Version       string               `json:"version" yaml:"version"`
+	EffectiveFrom string               `json:"effectiveFrom,omitempty" yaml:"effectiveFrom,omitempty"`
+	Adjustments   []SeasonalAdjustment `json:"adjustments" yaml:"adjustments"`
+	Tax           TaxRule              `json:"tax" yaml:"tax"`
+	Status        StatusRule           `json:"status" yaml:"status"`
+	Trend         *TrendRule           `json:"trend,omitempty" yaml:"trend,omitempty"`
+}
+
+// SeasonalAdjustment multiplies the salaries of the given months.
+type SeasonalAdjustment struct {
+	Name       string  `json:"name" yaml:"name"`
+	Months     []int   `json:"months" yaml:"months"`
+	Multiplier float64 `json:"multiplier" yaml:"multiplier"`
+}
+
+// Tax modes and bases.
+const (
+	TaxModeFlat        = "flat"
+	TaxModeProgressive = "progressive"
+
+	TaxBasisAggregate = "aggregate"
+	TaxBasisMonthly   = "monthly"
+)
+
+// TaxRule describes the tax deduction. In flat mode (the default) Rate is
+// deducted from every salary once the adjusted total exceeds Threshold. In
+// progressive mode Brackets are applied marginally, either to each month or to
+// the aggregate with the tax allocated back to months in proportion to their
+// amounts.
+type TaxRule struct {
+	Mode      string       `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Threshold float64      `json:"threshold" yaml:"threshold"`
+	Rate      float64      `json:"rate" yaml:"rate"`
+	Basis     string       `json:"basis,omitempty" yaml:"basis,omitempty"`
+	Brackets  []TaxBracket `json:"brackets,omitempty" yaml:"brackets,omitempty"`
+}
+
+// TaxBracket taxes the part of an amount above From, up to the next
+// bracket's From, at Rate.
+type TaxBracket struct {
+	From float64 `json:"from" yaml:"from"`
+	Rate float64 `json:"rate" yaml:"rate"`
+}
+
+// BracketTax is the tax charged within one bracket.
+type BracketTax struct {
+	From    float64
+	To      float64 // zero for the top bracket
+	Rate    float64
+	Taxable decimal.Decimal
+	Tax     decimal.Decimal
+}
+
+func (t TaxRule) IsProgressive() bool {
+	return t.Mode == TaxModeProgressive
+}
+
+// IsMonthly reports whether progressive brackets apply to each month.
+func (t TaxRule) IsMonthly() bool {
+	return t.Basis == TaxBasisMonthly
+}
+
+// ProgressiveTax splits the tax on amount across the brackets. Brackets the
+// amount does not reach are included with zero tax.
+func (t TaxRule) ProgressiveTax(amount decimal.Decimal) []BracketTax {
+	result := make([]BracketTax, len(t.Brackets))
+	for i, bracket := range t.Brackets {
+		entry := BracketTax{From: bracket.From, Rate: bracket.Rate}
+		from := decimal.NewFromFloat(bracket.From)
+		upper := amount
+		if i+1 < len(t.Brackets) {
+			entry.To = t.Brackets[i+1].From
+			upper = decimal.Min(amount, decimal.NewFromFloat(entry.To))
+		}
+		if upper.GreaterThan(from) {
+			entry.Taxable = upper.Sub(from)
+			entry.Tax = entry.Taxable.Mul(decimal.NewFromFloat(bracket.Rate))
+		}
+		result[i] = entry
+	}
+	return result
+}
+
+// StatusRule classifies the final average: above Threshold is GREEN, exactly
+// Threshold is ORANGE and below is RED.
+type StatusRule struct {
+	Threshold float64 `json:"threshold" yaml:"threshold"`
+}
+
+// TrendRule enables the trend classification. A series whose least-squares
+// slope per month is within StableBand of its average salary, as a fraction,
+// is STABLE; above is RISING and below is FALLING.
+type TrendRule struct {
+	StableBand float64 `json:"stableBand" yaml:"stableBand"`
+}
+
+// Default returns the rules the service has always applied.
+func Default() *RuleSet {
+	return &RuleSet{
+		Version: "default",
+		Adjustments: []SeasonalAdjustment{
+			{Name: "December holiday bonus", Months: []int{12}, Multiplier: 1.10},
+			{Name: "Summer deduction", Months: []int{6, 7, 8}, Multiplier: 0.95},
+		},
+		Tax: TaxRule{
+			Mode:      TaxModeFlat,
+			Threshold: 10000,
+			Rate:      0.07,
+		},
+		Status: StatusRule{
+			Threshold: 2000,
+		},
+	}
+}
+
+// Multiplier returns the combined seasonal multiplier for a month. Rule
+// values are taken at their shortest decimal representation, so 1.10 is
+// exactly 1.1.
+func (r *RuleSet) Multiplier(month int) decimal.Decimal {
+	multiplier := decimal.NewFromInt(1)
+	for _, adjustment := range r.Adjustments {
+		if adjustment.Applies(month) {
+			multiplier = multiplier.Mul(decimal.NewFromFloat(adjustment.Multiplier))
+		}
+	}
+	return multiplier
+}
+
+// AdjustmentNames lists the seasonal adjustments that apply to a month.
+func (r *RuleSet) AdjustmentNames(month int) []string {
+	var names []string
+	for _, adjustment := range r.Adjustments {
+		if adjustment.Applies(month) {
+			names = append(names, adjustment.Name)
+		}
+	}
+	return names
+}
+
+func (a SeasonalAdjustment) Applies(month int) bool {
+	for _, m := range a.Months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RuleSet) Validate() error {
+	if r.Version == "" {
+		return errors.New("rule set version is required")
+	}
+	if _, err := r.effectiveMonth(); err != nil {
+		return err
+	}
+
+	for i, adjustment := range r.Adjustments {
+		if len(adjustment.Months) == 0 {
+			return fmt.Errorf("adjustment %d (%s): at least one month is required", i, adjustment.Name)
+		}
+		for _, month := range adjustment.Months {
+			if month < 1 || month > 12 {
+				return fmt.Errorf("adjustment %d (%s): invalid month %d", i, adjustment.Name, month)
+			}
+		}
+		if adjustment.Multiplier <= 0 {
+			return fmt.Errorf("adjustment %d (%s): multiplier must be positive", i, adjustment.Name)
+		}
+	}
+
+	if err := r.Tax.validate(); err != nil {
+		return err
+	}
+	if r.Status.Threshold <= 0 {
+		return errors.New("status threshold must be positive")
+	}
+	if r.Trend != nil && (r.Trend.StableBand < 0 || r.Trend.StableBand >= 1) {
+		return errors.New("trend stable band must be in [0, 1)")
+	}
+
+	return nil
+}
+
+func (t TaxRule) validate() error {
+	switch t.Mode {
+	case "", TaxModeFlat:
+		if t.Threshold < 0 {
+			return errors.New("tax threshold must not be negative")
+		}
+		if t.Rate < 0 || t.Rate >= 1 {
+			return errors.New("tax rate must be in [0, 1)")
+		}
+	case TaxModeProgressive:
+		if t.Basis != "" && t.Basis != TaxBasisAggregate && t.Basis != TaxBasisMonthly {
+			return fmt.Errorf("tax basis must be %s or %s", TaxBasisAggregate, TaxBasisMonthly)
+		}
+		if len(t.Brackets) == 0 {
+			return errors.New("progressive tax requires at least one bracket")
+		}
+		for i, bracket := range t.Brackets {
+			if bracket.From < 0 {
+				return fmt.Errorf("tax bracket %d: from must not be negative", i)
+			}
+			if i > 0 && bracket.From <= t.Brackets[i-1].From {
+				return fmt.Errorf("tax bracket %d: brackets must be in ascending order", i)
+			}
+			if bracket.Rate < 0 || bracket.Rate >= 1 {
+				return fmt.Errorf("tax bracket %d: rate must be in [0, 1)", i)
+			}
+		}
+	default:
+		return fmt.Errorf("tax mode must be %s or %s", TaxModeFlat, TaxModeProgressive)
+	}
+	return nil
+}
+
+// Parse decodes and validates a rule set. format is "json" or "yaml".
+func Parse(data []byte, format string) (*RuleSet, error) {
+	var ruleSet RuleSet
+	var err error
+
+	switch format {
+	case "json":
+		err = json.Unmarshal(data, &ruleSet)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &ruleSet)
+	default:
+		return nil, fmt.Errorf("unsupported rule format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+
+	if err := ruleSet.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+
+	return &ruleSet, nil
+}
@@ -0,0 +1,109 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault_IsValid(t *testing.T) {
+	ruleSet := Default()
+
+	require.NoError(t, ruleSet.Validate())
+	assert.Equal(t, "1.1", ruleSet.Multiplier(12).String())
+	assert.Equal(t, "0.95", ruleSet.Multiplier(7).String())
+	assert.Equal(t, "1", ruleSet.Multiplier(3).String())
+}
+
+func TestLoadHistoryFile(t *testing.T) {
+	t.Run("Example YAML file", func(t *testing.T) {
+		history, err := LoadHistoryFile("../../docs/rules.example.yaml")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2025"}, history.Versions())
+		ruleSet := history.Latest()
+		assert.Len(t, ruleSet.Adjustments, 2)
+		assert.Equal(t, Default().Tax, ruleSet.Tax)
+		assert.Equal(t, Default().Status, ruleSet.Status)
+	})
+
+	t.Run("JSON file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "rules.json")
+		document := `{"version": "v2", "tax": {"threshold": 12000, "rate": 0.1}, "status": {"threshold": 2500}}`
+		require.NoError(t, os.WriteFile(path, []byte(document), 0o600))
+
+		history, err := LoadHistoryFile(path)
+
+		require.NoError(t, err)
+		ruleSet := history.Latest()
+		assert.Equal(t, "v2", ruleSet.Version)
+		assert.Empty(t, ruleSet.Adjustments)
+		assert.Equal(t, 12000.0, ruleSet.Tax.Threshold)
+	})
+
+	t.Run("Unsupported extension", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "rules.toml")
+		require.NoError(t, os.WriteFile(path, []byte(`version = "v2"`), 0o600))
+
+		_, err := LoadHistoryFile(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestRuleSet_Validate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(*RuleSet)
+	}{
+		{"Missing version", func(r *RuleSet) { r.Version = "" }},
+		{"Invalid month", func(r *RuleSet) { r.Adjustments[0].Months = []int{13} }},
+		{"Adjustment without months", func(r *RuleSet) { r.Adjustments[0].Months = nil }},
+		{"Non-positive multiplier", func(r *RuleSet) { r.Adjustments[1].Multiplier = 0 }},
+		{"Negative tax threshold", func(r *RuleSet) { r.Tax.Threshold = -1 }},
+		{"Tax rate of 100%", func(r *RuleSet) { r.Tax.Rate = 1 }},
+		{"Zero status threshold", func(r *RuleSet) { r.Status.Threshold = 0 }},
+		{"Unknown tax mode", func(r *RuleSet) { r.Tax.Mode = "stepped" }},
+		{"Progressive without brackets", func(r *RuleSet) { r.Tax = TaxRule{Mode: TaxModeProgressive} }},
+		{"Unsorted brackets", func(r *RuleSet) {
+			r.Tax = TaxRule{Mode: TaxModeProgressive, Brackets: []TaxBracket{{From: 10000, Rate: 0.1}, {From: 5000, Rate: 0.2}}}
+		}},
+		{"Unknown tax basis", func(r *RuleSet) {
+			r.Tax = TaxRule{Mode: TaxModeProgressive, Basis: "yearly", Brackets: []TaxBracket{{From: 0, Rate: 0.1}}}
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ruleSet := Default()
+			tc.modify(ruleSet)
+			assert.Error(t, ruleSet.Validate())
+		})
+	}
+}
+
+func TestTaxRule_ProgressiveTax(t *testing.T) {
+	taxRule := TaxRule{
+		Mode: TaxModeProgressive,
+		Brackets: []TaxBracket{
+			{From: 0, Rate: 0},
+			{From: 10000, Rate: 0.07},
+			{From: 20000, Rate: 0.15},
+		},
+	}
+
+	brackets := taxRule.ProgressiveTax(decimal.NewFromInt(15000))
+
+	require.Len(t, brackets, 3)
+	assert.Equal(t, 10000.0, brackets[0].To)
+	assert.True(t, brackets[0].Taxable.Equal(decimal.NewFromInt(10000)))
+	assert.True(t, brackets[0].Tax.IsZero())
+	assert.Equal(t, 20000.0, brackets[1].To)
+	assert.True(t, brackets[1].Taxable.Equal(decimal.NewFromInt(5000)))
+	assert.True(t, brackets[1].Tax.Equal(decimal.NewFromInt(350)))
+	assert.True(t, brackets[2].Taxable.IsZero())
+	assert.True(t, brackets[2].Tax.IsZero())
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+)
+
+type APIKeyService struct {
+	repo   repository.APIKeyRepository
+	pepper string
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository, pepper string) *APIKeyService {
+	return &APIKeyService{
+		repo:   repo,
+		pepper: pepper,
+	}
+}
+
+// Create issues a new key. The plaintext key is only ever returned here.
+func (s *APIKeyService) Create(ctx context.Context, owner string, scopes []string, tier string, ttl time.Duration) (*models.APIKey, string, error) {
+	key, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	record := &models.APIKey{
+		KeyPrefix: prefix,
+		KeyHash:   auth.HashAPIKey(s.pepper, key),
+		Owner:     owner,
+		Scopes:    strings.Join(scopes, " "),
+		Tier:      tier,
+	}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		record.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.Create(ctx, record); err != nil {
+		return nil, "", fmt.Errorf("failed to store api key: %w", err)
+	}
+
+	return record, key, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]models.APIKey, error) {
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id int64) error {
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return &AppError{
+				Code:    404,
+				Message: "API key not found",
+			}
+		}
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+)
+
+const defaultAuditLimit = 100
+
+type requestInfoKey struct{}
+
+// RequestInfo describes who made the current request. Handlers attach it to
+// the context so services can audit access.
+type RequestInfo struct {
+	Caller     string
+	AuthMethod string
+	ClientIP   string
+	RequestID  string
+}
+
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request info attached to ctx, or the
+// zero value when there is none.
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+// recordAccess audits a lookup of an employee's salary data with the status
+// code it resulted in. It is a no-op without a repository.
+func recordAccess(ctx context.Context, repo repository.AccessAuditRepository, nationalNumber string, lookupErr error) error {
+	if repo == nil {
+		return nil
+	}
+
+	resultCode := 200
+	if lookupErr != nil {
+		resultCode = 500
+		var appErr *AppError
+		if errors.As(lookupErr, &appErr) {
+			resultCode = appErr.Code
+		}
+	}
+
+	info := RequestInfoFromContext(ctx)
+	return repo.Create(ctx, &models.AccessAudit{
+		Caller:         info.Caller,
+		AuthMethod:     info.AuthMethod,
+		NationalNumber: nationalNumber,
+		ResultCode:     resultCode,
+		ClientIP:       info.ClientIP,
+		RequestID:      info.RequestID,
+	})
+}
+
+type AuditService struct {
+	repo repository.AccessAuditRepository
+}
+
+func NewAuditService(repo repository.AccessAuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// List returns the newest audit entries matching query.
+func (s *AuditService) List(ctx context.Context, query models.AccessAuditQuery) ([]models.AccessAudit, error) {
+	if query.Limit == 0 {
+		query.Limit = defaultAuditLimit
+	}
+	return s.repo.List(ctx, query)
+}
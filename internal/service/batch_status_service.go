@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+type BatchStatusService struct {
+	statuses *ProcessStatusService
+	workers  int
+}
+
+func NewBatchStatusService(statuses *ProcessStatusService, workers int) *BatchStatusService {
+	return &BatchStatusService{
+		statuses: statuses,
+		workers:  workers,
+	}
+}
+
+// GetEmployeeStatuses looks up each national number as GetEmployeeStatus
+// would, including the access audit, with at most the configured number of
+// lookups at a time. Results are in request order. A lookup refused for the
+// employee's own data, such as a 404, 406 or a 422 for insufficient data or a
+// missing exchange rate, carries that error in its result; only faults such as
+// an unreachable database stop the batch and are returned.
+func (s *BatchStatusService) GetEmployeeStatuses(ctx context.Context, nationalNumbers []string) ([]models.BatchStatusResult, error) {
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]models.BatchStatusResult, len(nationalNumbers))
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		batchErr error
+	)
+	for w := 0; w < min(s.workers, len(nationalNumbers)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := s.lookup(batchCtx, nationalNumbers[i])
+				if err != nil {
+					failOnce.Do(func() {
+						batchErr = err
+						cancel()
+					})
+					continue
+				}
+				results[i] = result
+			}
+		}()
+	}
+
+feed:
+	for i := range nationalNumbers {
+		select {
+		case jobs <- i:
+		case <-batchCtx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if batchErr != nil {
+		return nil, batchErr
+	}
+	// The caller may have gone away before every lookup was started
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *BatchStatusService) lookup(ctx context.Context, nationalNumber string) (models.BatchStatusResult, error) {
+	result := models.BatchStatusResult{NationalNumber: nationalNumber}
+
+	employeeInfo, err := s.statuses.GetEmployeeStatus(ctx, nationalNumber)
+	if err != nil {
+		if !statusUnavailable(err) {
+			return result, err
+		}
+		var appErr *AppError
+		errors.As(err, &appErr)
+		result.Code = appErr.Code
+		result.Error = &models.ErrorResponse{
+			Error:   appErr.Message,
+			Details: appErr.Details,
+		}
+		return result, nil
+	}
+
+	result.Code = 200
+	result.Employee = employeeInfo
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyProbe is a UserRepository that records the most lookups in
+// flight at once.
+type concurrencyProbe struct {
+	MockUserRepository
+	inFlight, peak atomic.Int32
+}
+
+func (p *concurrencyProbe) GetByNationalNumber(ctx context.Context, nationalNumber string) (*models.User, error) {
+	current := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if current <= peak || p.peak.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return p.MockUserRepository.GetByNationalNumber(ctx, nationalNumber)
+}
+
+func TestBatchStatusService_GetEmployeeStatuses(t *testing.T) {
+	ctx := context.Background()
+	salaries := []models.Salary{
+		{Year: 2024, Month: 1, Salary: decimal.NewFromFloat(2500)},
+		{Year: 2024, Month: 2, Salary: decimal.NewFromFloat(2500)},
+		{Year: 2024, Month: 3, Salary: decimal.NewFromFloat(2500)},
+	}
+
+	t.Run("Results in request order with their own errors", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockSalaryRepo := new(MockSalaryRepository)
+		statuses := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		service := NewBatchStatusService(statuses, 2)
+
+		mockUserRepo.On("GetByNationalNumber", mock.Anything, "NAT1001").Return(&models.User{ID: 1, NationalNumber: "NAT1001", IsActive: true}, nil)
+		mockUserRepo.On("GetByNationalNumber", mock.Anything, "NAT1002").Return(&models.User{ID: 2, NationalNumber: "NAT1002", IsActive: false}, nil)
+		mockUserRepo.On("GetByNationalNumber", mock.Anything, "NAT9999").Return((*models.User)(nil), errors.New("user with national number NAT9999 not found"))
+		mockSalaryRepo.On("CountByUserID", mock.Anything, int64(1)).Return(3, nil)
+		mockSalaryRepo.On("GetByUserID", mock.Anything, int64(1)).Return(salaries, nil)
+
+		results, err := service.GetEmployeeStatuses(ctx, []string{"NAT9999", "NAT1001", "NAT1002"})
+
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, "NAT9999", results[0].NationalNumber)
+		assert.Equal(t, 404, results[0].Code)
+		assert.Equal(t, "Invalid National Number", results[0].Error.Error)
+		assert.Equal(t, 200, results[1].Code)
+		assert.Equal(t, StatusGreen, results[1].Employee.Status)
+		assert.Nil(t, results[1].Error)
+		assert.Equal(t, 406, results[2].Code)
+		assert.Nil(t, results[2].Employee)
+	})
+
+	t.Run("Missing exchange rate is a per-item error", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockSalaryRepo := new(MockSalaryRepository)
+		mockRateRepo := new(MockExchangeRateRepository)
+		statuses := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		statuses.SetExchangeRateRepository(mockRateRepo)
+		service := NewBatchStatusService(statuses, 2)
+
+		foreign := []models.Salary{
+			{Year: 2024, Month: 1, Salary: decimal.NewFromFloat(3000), Currency: "USD"},
+			{Year: 2024, Month: 2, Salary: decimal.NewFromFloat(3000), Currency: "USD"},
+			{Year: 2024, Month: 3, Salary: decimal.NewFromFloat(3000), Currency: "USD"},
+		}
+		mockUserRepo.On("GetByNationalNumber", mock.Anything, "NAT1001").Return(&models.User{ID: 1, NationalNumber: "NAT1001", IsActive: true}, nil)
+		mockUserRepo.On("GetByNationalNumber", mock.Anything, "NAT1002").Return(&models.User{ID: 2, NationalNumber: "NAT1002", IsActive: true}, nil)
+		mockSalaryRepo.On("CountByUserID", mock.Anything, int64(1)).Return(3, nil)
+		mockSalaryRepo.On("GetByUserID", mock.Anything, int64(1)).Return(foreign, nil)
+		mockSalaryRepo.On("CountByUserID", mock.Anything, int64(2)).Return(3, nil)
+		mockSalaryRepo.On("GetByUserID", mock.Anything, int64(2)).Return(salaries, nil)
+		mockRateRepo.On("GetRates", mock.Anything, []string{"USD"}, mock.Anything, mock.Anything).Return([]models.ExchangeRate{}, nil)
+
+		results, err := service.GetEmployeeStatuses(ctx, []string{"NAT1001", "NAT1002"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 422, results[0].Code)
+		assert.Equal(t, "MISSING_EXCHANGE_RATE", results[0].Error.Error)
+		assert.Equal(t, 200, results[1].Code)
+	})
+
+	t.Run("Lookups are bounded by the worker count", func(t *testing.T) {
+		probe := new(concurrencyProbe)
+		statuses := NewProcessStatusService(probe, new(MockSalaryRepository), nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		service := NewBatchStatusService(statuses, 3)
+
+		nationalNumbers := make([]string, 12)
+		for i := range nationalNumbers {
+			nationalNumbers[i] = fmt.Sprintf("NAT%04d", i)
+			probe.On("GetByNationalNumber", mock.Anything, nationalNumbers[i]).Return(&models.User{ID: int64(i), IsActive: false}, nil)
+		}
+
+		results, err := service.GetEmployeeStatuses(ctx, nationalNumbers)
+
+		require.NoError(t, err)
+		assert.Len(t, results, 12)
+		assert.LessOrEqual(t, probe.peak.Load(), int32(3))
+	})
+
+	t.Run("Unexpected failure fails the batch", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		statuses := NewProcessStatusService(mockUserRepo, new(MockSalaryRepository), nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		service := NewBatchStatusService(statuses, 2)
+		mockUserRepo.On("GetByNationalNumber", mock.Anything, mock.Anything).Return((*models.User)(nil), errors.New("connection refused"))
+
+		results, err := service.GetEmployeeStatuses(ctx, []string{"NAT1001", "NAT1002", "NAT1003"})
+
+		assert.Error(t, err)
+		assert.Nil(t, results)
+	})
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// DepartmentService answers questions about groups of employees, either a
+// department with its sub-departments or everyone reporting to a manager.
+// Statuses are read from the stored salary ranking rather than calculated per
+// employee, so views are as current as the last ranking refresh.
+type DepartmentService struct {
+	departmentRepo repository.DepartmentRepository
+	userRepo       repository.UserRepository
+	rankingRepo    repository.SalaryRankingRepository
+	auditRepo      repository.AccessAuditRepository
+	currency       string
+}
+
+func NewDepartmentService(
+	departmentRepo repository.DepartmentRepository,
+	userRepo repository.UserRepository,
+	rankingRepo repository.SalaryRankingRepository,
+	auditRepo repository.AccessAuditRepository,
+	currency string,
+) *DepartmentService {
+	return &DepartmentService{
+		departmentRepo: departmentRepo,
+		userRepo:       userRepo,
+		rankingRepo:    rankingRepo,
+		auditRepo:      auditRepo,
+		currency:       currency,
+	}
+}
+
+// teamRanking pairs a group of active employees with their stored rankings.
+// Rankings are nil for employees left out of the ranking, such as those with
+// insufficient salary data.
+type teamRanking struct {
+	members  []models.User
+	rankings []*models.SalaryRanking
+}
+
+// StatusSummary aggregates the statuses of a department's active employees,
+// including those of its sub-departments.
+func (s *DepartmentService) StatusSummary(ctx context.Context, departmentID int64) (*models.DepartmentStatusSummary, error) {
+	departments, team, err := s.departmentTeam(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DepartmentStatusSummary{
+		Department:     departments[0],
+		SubDepartments: len(departments) - 1,
+		StatusSummary:  s.summarize(team),
+	}, nil
+}
+
+// RedEmployees lists a department's active employees with a RED status,
+// including those of its sub-departments. Each employee listed is recorded in
+// the access audit trail.
+func (s *DepartmentService) RedEmployees(ctx context.Context, departmentID int64) ([]models.DepartmentEmployee, error) {
+	_, team, err := s.departmentTeam(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.redEmployees(ctx, team)
+}
+
+// TeamStatusSummary aggregates the statuses of the active employees reporting
+// to the manager with the given national number, directly or indirectly.
+func (s *DepartmentService) TeamStatusSummary(ctx context.Context, nationalNumber string) (*models.TeamStatusSummary, error) {
+	manager, team, err := s.managerTeam(ctx, nationalNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.TeamStatusSummary{
+		ManagerID:     manager.ID,
+		Manager:       manager.Username,
+		StatusSummary: s.summarize(team),
+	}
+	for _, member := range team.members {
+		if member.ManagerID != nil && *member.ManagerID == manager.ID {
+			summary.DirectReports++
+		}
+	}
+
+	return summary, nil
+}
+
+// TeamRedEmployees lists the active employees with a RED status reporting to
+// the manager with the given national number, directly or indirectly. Each
+// employee listed is recorded in the access audit trail.
+func (s *DepartmentService) TeamRedEmployees(ctx context.Context, nationalNumber string) ([]models.DepartmentEmployee, error) {
+	_, team, err := s.managerTeam(ctx, nationalNumber)
+	if err != nil {
+		return nil, err
+	}
+	return s.redEmployees(ctx, team)
+}
+
+func (s *DepartmentService) departmentTeam(ctx context.Context, departmentID int64) ([]models.Department, *teamRanking, error) {
+	departments, err := s.departmentRepo.ListSubtree(ctx, departmentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, &AppError{
+			Code:    404,
+			Message: "Department not found",
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	departmentIDs := make([]int64, len(departments))
+	for i, department := range departments {
+		departmentIDs[i] = department.ID
+	}
+
+	members, err := s.departmentRepo.ListActiveMembers(ctx, departmentIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	team, err := s.rankTeam(ctx, members)
+	if err != nil {
+		return nil, nil, err
+	}
+	return departments, team, nil
+}
+
+func (s *DepartmentService) managerTeam(ctx context.Context, nationalNumber string) (*models.User, *teamRanking, error) {
+	manager, err := findUser(ctx, s.userRepo, nationalNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	members, err := s.departmentRepo.ListActiveReports(ctx, manager.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	team, err := s.rankTeam(ctx, members)
+	if err != nil {
+		return nil, nil, err
+	}
+	return manager, team, nil
+}
+
+// rankTeam reads the stored rankings of all members in a single query.
+func (s *DepartmentService) rankTeam(ctx context.Context, members []models.User) (*teamRanking, error) {
+	team := &teamRanking{
+		members:  members,
+		rankings: make([]*models.SalaryRanking, len(members)),
+	}
+	if len(members) == 0 {
+		return team, nil
+	}
+
+	userIDs := make([]int64, len(members))
+	for i, member := range members {
+		userIDs[i] = member.ID
+	}
+
+	rankings, err := s.rankingRepo.ListByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[int64]*models.SalaryRanking, len(rankings))
+	for i := range rankings {
+		byUser[rankings[i].UserID] = &rankings[i]
+	}
+	for i, member := range members {
+		team.rankings[i] = byUser[member.ID]
+	}
+
+	return team, nil
+}
+
+func (s *DepartmentService) summarize(team *teamRanking) models.StatusSummary {
+	summary := models.StatusSummary{
+		Employees: len(team.members),
+		StatusDistribution: map[string]int{
+			StatusGreen:  0,
+			StatusOrange: 0,
+			StatusRed:    0,
+		},
+		Currency: s.currency,
+	}
+
+	sum := decimal.Zero
+	for _, ranking := range team.rankings {
+		if ranking == nil {
+			summary.InsufficientData++
+			continue
+		}
+		summary.StatusDistribution[ranking.Status]++
+		sum = sum.Add(ranking.AverageSalary)
+		if summary.RefreshedAt == nil || ranking.RefreshedAt.After(*summary.RefreshedAt) {
+			refreshedAt := ranking.RefreshedAt
+			summary.RefreshedAt = &refreshedAt
+		}
+	}
+	if ranked := summary.Employees - summary.InsufficientData; ranked > 0 {
+		summary.AverageSalary = sum.Div(decimal.NewFromInt(int64(ranked))).Round(moneyPlaces).InexactFloat64()
+	}
+
+	return summary
+}
+
+func (s *DepartmentService) redEmployees(ctx context.Context, team *teamRanking) ([]models.DepartmentEmployee, error) {
+	employees := []models.DepartmentEmployee{}
+	for i, member := range team.members {
+		ranking := team.rankings[i]
+		if ranking == nil || ranking.Status != StatusRed {
+			continue
+		}
+
+		employee := models.DepartmentEmployee{
+			ID:             member.ID,
+			Username:       member.Username,
+			NationalNumber: member.NationalNumber,
+			Status:         ranking.Status,
+			AverageSalary:  ranking.AverageSalary.InexactFloat64(),
+			Currency:       ranking.Currency,
+		}
+		if member.DepartmentID != nil {
+			employee.DepartmentID = *member.DepartmentID
+		}
+		employees = append(employees, employee)
+	}
+
+	// Salary data is not released without an audit record
+	for _, employee := range employees {
+		if err := recordAccess(ctx, s.auditRepo, employee.NationalNumber, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return employees, nil
+}
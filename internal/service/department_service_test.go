@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepartmentService(t *testing.T) {
+	ctx := WithRequestInfo(context.Background(), RequestInfo{Caller: "team-lead", AuthMethod: "jwt"})
+	refreshedAt := time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC)
+	engineering, platform := int64(2), int64(5)
+	lead, manager := int64(4), int64(1)
+	departments := []models.Department{
+		{ID: engineering, Name: "Engineering"},
+		{ID: platform, Name: "Platform", ParentID: &engineering},
+	}
+	members := []models.User{
+		{ID: 1, Username: "green", NationalNumber: "NAT1001", DepartmentID: &engineering, IsActive: true},
+		{ID: 2, Username: "red", NationalNumber: "NAT1002", DepartmentID: &platform, ManagerID: &lead, IsActive: true},
+		{ID: 3, Username: "new_hire", NationalNumber: "NAT1003", DepartmentID: &platform, ManagerID: &lead, IsActive: true},
+	}
+	ranking := func(userID int64, status string, average float64) models.SalaryRanking {
+		return models.SalaryRanking{
+			UserID:         userID,
+			Status:         status,
+			AverageSalary:  decimal.NewFromFloat(average),
+			Currency:       "JOD",
+			PercentileRank: models.PercentileRank{RefreshedAt: refreshedAt},
+		}
+	}
+	rankings := []models.SalaryRanking{ranking(1, StatusGreen, 2500), ranking(2, StatusRed, 1500)}
+
+	newService := func() (*DepartmentService, *MockDepartmentRepository, *MockUserRepository, *MockSalaryRankingRepository, *MockAccessAuditRepository) {
+		mockDepartmentRepo := new(MockDepartmentRepository)
+		mockUserRepo := new(MockUserRepository)
+		mockRankingRepo := new(MockSalaryRankingRepository)
+		mockAuditRepo := new(MockAccessAuditRepository)
+
+		mockDepartmentRepo.On("ListSubtree", ctx, engineering).Return(departments, nil)
+		mockDepartmentRepo.On("ListActiveMembers", ctx, []int64{engineering, platform}).Return(members, nil)
+		mockRankingRepo.On("ListByUserIDs", ctx, []int64{1, 2, 3}).Return(rankings, nil)
+		service := NewDepartmentService(mockDepartmentRepo, mockUserRepo, mockRankingRepo, mockAuditRepo, "JOD")
+		return service, mockDepartmentRepo, mockUserRepo, mockRankingRepo, mockAuditRepo
+	}
+
+	t.Run("Summary covers sub-departments", func(t *testing.T) {
+		service, _, _, mockRankingRepo, mockAuditRepo := newService()
+
+		summary, err := service.StatusSummary(ctx, engineering)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Engineering", summary.Department.Name)
+		assert.Equal(t, 1, summary.SubDepartments)
+		assert.Equal(t, 3, summary.Employees)
+		assert.Equal(t, map[string]int{StatusGreen: 1, StatusOrange: 0, StatusRed: 1}, summary.StatusDistribution)
+		assert.Equal(t, 1, summary.InsufficientData)
+		assert.Equal(t, 2000.0, summary.AverageSalary)
+		assert.Equal(t, "JOD", summary.Currency)
+		require.NotNil(t, summary.RefreshedAt)
+		assert.Equal(t, refreshedAt, *summary.RefreshedAt)
+		// Statuses come from the ranking in one query, not per employee
+		mockRankingRepo.AssertNumberOfCalls(t, "ListByUserIDs", 1)
+		mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("RED employees are listed and audited", func(t *testing.T) {
+		service, _, _, _, mockAuditRepo := newService()
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(entry *models.AccessAudit) bool {
+			return entry.Caller == "team-lead" && entry.NationalNumber == "NAT1002" && entry.ResultCode == 200
+		})).Return(nil)
+
+		employees, err := service.RedEmployees(ctx, engineering)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.DepartmentEmployee{{
+			ID:             2,
+			Username:       "red",
+			NationalNumber: "NAT1002",
+			DepartmentID:   platform,
+			Status:         StatusRed,
+			AverageSalary:  1500,
+			Currency:       "JOD",
+		}}, employees)
+		mockAuditRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("Unknown department", func(t *testing.T) {
+		service, mockDepartmentRepo, _, _, _ := newService()
+		mockDepartmentRepo.On("ListSubtree", ctx, int64(99)).Return(nil, fmt.Errorf("department 99 %w", repository.ErrNotFound))
+
+		summary, err := service.StatusSummary(ctx, 99)
+
+		assert.Nil(t, summary)
+		var appErr *AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 404, appErr.Code)
+	})
+
+	t.Run("Team summary covers indirect reports", func(t *testing.T) {
+		service, mockDepartmentRepo, mockUserRepo, mockRankingRepo, _ := newService()
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT0001").Return(&models.User{ID: manager, Username: "director", IsActive: true}, nil)
+		// The lead reports to the director; the others report to the lead
+		reports := append([]models.User{{ID: lead, Username: "lead", NationalNumber: "NAT1004", ManagerID: &manager, IsActive: true}}, members[1:]...)
+		mockDepartmentRepo.On("ListActiveReports", ctx, manager).Return(reports, nil)
+		mockRankingRepo.On("ListByUserIDs", ctx, []int64{lead, 2, 3}).Return([]models.SalaryRanking{ranking(2, StatusRed, 1500), ranking(lead, StatusOrange, 1900)}, nil)
+
+		summary, err := service.TeamStatusSummary(ctx, "NAT0001")
+
+		require.NoError(t, err)
+		assert.Equal(t, manager, summary.ManagerID)
+		assert.Equal(t, "director", summary.Manager)
+		assert.Equal(t, 1, summary.DirectReports)
+		assert.Equal(t, 3, summary.Employees)
+		assert.Equal(t, map[string]int{StatusGreen: 0, StatusOrange: 1, StatusRed: 1}, summary.StatusDistribution)
+		assert.Equal(t, 1, summary.InsufficientData)
+		assert.Equal(t, 1700.0, summary.AverageSalary)
+	})
+
+	t.Run("Team without reports", func(t *testing.T) {
+		service, mockDepartmentRepo, mockUserRepo, mockRankingRepo, _ := newService()
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return(&members[0], nil)
+		mockDepartmentRepo.On("ListActiveReports", ctx, int64(1)).Return([]models.User{}, nil)
+
+		employees, err := service.TeamRedEmployees(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		assert.Empty(t, employees)
+		mockRankingRepo.AssertNotCalled(t, "ListByUserIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown manager", func(t *testing.T) {
+		service, _, mockUserRepo, _, _ := newService()
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT9999").Return((*models.User)(nil), errors.New("user with national number NAT9999 not found"))
+
+		summary, err := service.TeamStatusSummary(ctx, "NAT9999")
+
+		assert.Nil(t, summary)
+		var appErr *AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 404, appErr.Code)
+	})
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency is the reporting currency unless one is configured.
+const DefaultCurrency = "JOD"
+
+type exchangeRateKey struct {
+	currency string
+	period   models.Period
+}
+
+// ExchangeRates holds the monthly value of foreign currencies in the reporting
+// currency.
+type ExchangeRates map[exchangeRateKey]decimal.Decimal
+
+func NewExchangeRates(rates []models.ExchangeRate) ExchangeRates {
+	result := make(ExchangeRates, len(rates))
+	for _, rate := range rates {
+		result[exchangeRateKey{rate.Currency, models.Period{Year: rate.Year, Month: rate.Month}}] = rate.Rate
+	}
+	return result
+}
+
+// MissingExchangeRateError is returned for a salary paid in a currency without a
+// rate for its month.
+type MissingExchangeRateError struct {
+	Currency string
+	Period   models.Period
+}
+
+func (e *MissingExchangeRateError) Error() string {
+	return fmt.Sprintf("no %s exchange rate for %s", e.Currency, e.Period)
+}
+
+// foreignCurrencies lists the currencies other than reporting that salaries
+// are paid in, and the months they span.
+func foreignCurrencies(salaries []models.Salary, reporting string) ([]string, models.Period, models.Period) {
+	var (
+		currencies []string
+		from, to   models.Period
+		seen       = make(map[string]bool)
+	)
+	for _, salary := range salaries {
+		if salary.Currency == "" || salary.Currency == reporting {
+			continue
+		}
+		if !seen[salary.Currency] {
+			seen[salary.Currency] = true
+			currencies = append(currencies, salary.Currency)
+		}
+		period := models.Period{Year: salary.Year, Month: salary.Month}
+		if from == (models.Period{}) || period.Before(from) {
+			from = period
+		}
+		if to.Before(period) {
+			to = period
+		}
+	}
+	return currencies, from, to
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// comparisonPlaces is enough precision to show why an average just above or
+// below the threshold was not ORANGE.
+const comparisonPlaces = 6
+
+// StatusOptions changes what GetEmployeeStatusWithOptions computes. The zero
+// value is a plain status lookup.
+type StatusOptions struct {
+	Explain bool
+	Window  Window
+}
+
+// cacheKey keeps results computed with different options apart.
+func (o StatusOptions) cacheKey(base string) string {
+	if o.Explain {
+		return base + ":explain"
+	}
+	return base
+}
+
+func buildExplanation(calculation *SalaryCalculationResult) *models.Explanation {
+	explanation := &models.Explanation{
+		RuleVersion:     calculation.RuleVersion,
+		Currency:        calculation.Currency,
+		Months:          make([]models.MonthExplanation, len(calculation.Months)),
+		TotalBeforeTax:  money(calculation.TotalBeforeTax),
+		AverageSalary:   calculation.AverageSalary.InexactFloat64(),
+		StatusThreshold: calculation.StatusThreshold.InexactFloat64(),
+		Tax: models.TaxExplanation{
+			Mode:     calculation.Tax.Mode,
+			Basis:    calculation.Tax.Basis,
+			TotalTax: money(calculation.Tax.TotalTax),
+			Brackets: make([]models.TaxBracketBreakdown, len(calculation.Tax.Brackets)),
+		},
+	}
+
+	total := decimal.Zero
+	for i, month := range calculation.Months {
+		explanation.Months[i] = models.MonthExplanation{
+			Year:         month.Year,
+			Month:        month.Month,
+			RuleVersion:  month.RuleVersion,
+			Original:     money(month.Original),
+			Currency:     month.Currency,
+			ExchangeRate: month.ExchangeRate.InexactFloat64(),
+			Converted:    money(month.Converted),
+			Adjustments:  month.Adjustments,
+			Multiplier:   month.Multiplier.InexactFloat64(),
+			Adjusted:     money(month.Adjusted),
+			Tax:          money(month.Tax),
+			Final:        money(month.Final),
+		}
+		if explanation.Months[i].Adjustments == nil {
+			explanation.Months[i].Adjustments = []string{}
+		}
+		total = total.Add(month.Final)
+	}
+
+	for i, bracket := range calculation.Tax.Brackets {
+		explanation.Tax.Brackets[i] = models.TaxBracketBreakdown{
+			From:    bracket.From,
+			To:      bracket.To,
+			Rate:    bracket.Rate,
+			Taxable: money(bracket.Taxable),
+			Tax:     money(bracket.Tax),
+		}
+	}
+
+	average := decimal.Zero
+	if len(calculation.Months) > 0 {
+		average = total.Div(decimal.NewFromInt(int64(len(calculation.Months))))
+	}
+	explanation.Comparison = fmt.Sprintf("average %s %s threshold %s => %s",
+		average.Round(comparisonPlaces).String(),
+		comparisonOperator(calculation.Status),
+		calculation.StatusThreshold.String(),
+		calculation.Status,
+	)
+
+	return explanation
+}
+
+func comparisonOperator(status string) string {
+	switch status {
+	case StatusGreen:
+		return ">"
+	case StatusOrange:
+		return "="
+	default:
+		return "<"
+	}
+}
+
+// money rounds an exact amount to cents for display.
+func money(amount decimal.Decimal) float64 {
+	return amount.Round(moneyPlaces).InexactFloat64()
+}
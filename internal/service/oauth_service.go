@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+)
+
+// OAuth2 error codes from RFC 6749 section 5.2.
+const (
+	OAuthInvalidRequest       = "invalid_request"
+	OAuthInvalidClient        = "invalid_client"
+	OAuthInvalidScope         = "invalid_scope"
+	OAuthUnsupportedGrantType = "unsupported_grant_type"
+)
+
+// OAuthError is returned by the token endpoint flow and rendered in the
+// RFC 6749 error format rather than as an AppError.
+type OAuthError struct {
+	Status      int
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+type OAuthService struct {
+	clientRepo repository.OAuthClientRepository
+	issuer     *auth.TokenIssuer
+	pepper     string
+}
+
+func NewOAuthService(clientRepo repository.OAuthClientRepository, issuer *auth.TokenIssuer, pepper string) *OAuthService {
+	return &OAuthService{
+		clientRepo: clientRepo,
+		issuer:     issuer,
+		pepper:     pepper,
+	}
+}
+
+// IssueClientCredentialsToken implements the client_credentials grant. When
+// no scopes are requested, the client receives every scope it is allowed.
+func (s *OAuthService) IssueClientCredentialsToken(ctx context.Context, clientID, clientSecret string, requestedScopes []string) (*models.TokenResponse, error) {
+	invalidClient := &OAuthError{
+		Status:      401,
+		Code:        OAuthInvalidClient,
+		Description: "Client authentication failed",
+	}
+
+	client, err := s.clientRepo.GetByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, invalidClient
+		}
+		return nil, fmt.Errorf("failed to fetch oauth client: %w", err)
+	}
+
+	secretHash := auth.HashSecret(s.pepper, clientSecret)
+	if !hmac.Equal([]byte(secretHash), []byte(client.SecretHash)) || !client.IsActive {
+		return nil, invalidClient
+	}
+
+	allowed := auth.ParseScopes(client.Scopes)
+	granted := allowed
+	if len(requestedScopes) > 0 {
+		for _, scope := range requestedScopes {
+			if !containsString(allowed, scope) {
+				return nil, &OAuthError{
+					Status:      400,
+					Code:        OAuthInvalidScope,
+					Description: "Scope " + scope + " is not allowed for this client",
+				}
+			}
+		}
+		granted = requestedScopes
+	}
+
+	token, err := s.issuer.Issue(client.ClientID, granted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.issuer.TTL().Seconds()),
+		Scope:       strings.Join(granted, " "),
+	}, nil
+}
+
+// RegisterClient creates a client. The plaintext secret is only ever returned here.
+func (s *OAuthService) RegisterClient(ctx context.Context, name string, scopes []string, tier string) (*models.OAuthClient, string, error) {
+	clientID, clientSecret, err := auth.GenerateClientCredentials()
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := &models.OAuthClient{
+		ClientID:   clientID,
+		SecretHash: auth.HashSecret(s.pepper, clientSecret),
+		Name:       name,
+		Scopes:     strings.Join(scopes, " "),
+		Tier:       tier,
+	}
+	if err := s.clientRepo.Create(ctx, client); err != nil {
+		return nil, "", fmt.Errorf("failed to store oauth client: %w", err)
+	}
+
+	return client, clientSecret, nil
+}
+
+// TierFor returns the rate limit tier recorded for the OAuth client a token
+// was issued to. Subjects that are not clients issued by the service, and
+// unknown clients, get the default tier.
+func (s *OAuthService) TierFor(ctx context.Context, subject string) (string, error) {
+	if !strings.HasPrefix(subject, auth.ClientIDPrefix) {
+		return "", nil
+	}
+
+	client, err := s.clientRepo.GetByClientID(ctx, subject)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to fetch oauth client: %w", err)
+	}
+	return client.Tier, nil
+}
+
+func (s *OAuthService) ListClients(ctx context.Context) ([]models.OAuthClient, error) {
+	clients, err := s.clientRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth clients: %w", err)
+	}
+	return clients, nil
+}
+
+func (s *OAuthService) DeactivateClient(ctx context.Context, clientID string) error {
+	if err := s.clientRepo.Deactivate(ctx, clientID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return &AppError{
+				Code:    404,
+				Message: "OAuth client not found",
+			}
+		}
+		return fmt.Errorf("failed to deactivate oauth client: %w", err)
+	}
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rixtrayker/getemps-service/internal/auth"
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOAuthClientRepository is a mock implementation of OAuthClientRepository
+type MockOAuthClientRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthClientRepository) Create(ctx context.Context, client *models.OAuthClient) error {
+	return m.Called(ctx, client).Error(0)
+}
+
+func (m *MockOAuthClientRepository) GetByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).(*models.OAuthClient), args.Error(1)
+}
+
+func (m *MockOAuthClientRepository) List(ctx context.Context) ([]models.OAuthClient, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.OAuthClient), args.Error(1)
+}
+
+func (m *MockOAuthClientRepository) Deactivate(ctx context.Context, clientID string) error {
+	return m.Called(ctx, clientID).Error(0)
+}
+
+func TestOAuthService_IssueClientCredentialsToken(t *testing.T) {
+	const pepper = "test-pepper"
+	jwtConfig := auth.JWTConfig{Secret: "test-secret", Issuer: "getemps", Audience: "getemps-api"}
+	ctx := context.Background()
+
+	client := &models.OAuthClient{
+		ID:         1,
+		ClientID:   "cli_reconciliation",
+		SecretHash: auth.HashSecret(pepper, "correct-secret"),
+		Name:       "reconciliation",
+		Scopes:     "status:read pii:read",
+		Tier:       "batch",
+		IsActive:   true,
+	}
+
+	newService := func() (*OAuthService, *MockOAuthClientRepository) {
+		repo := new(MockOAuthClientRepository)
+		repo.On("GetByClientID", ctx, "cli_reconciliation").Return(client, nil)
+		repo.On("GetByClientID", ctx, "cli_unknown").Return((*models.OAuthClient)(nil), fmt.Errorf("oauth client %w", repository.ErrNotFound))
+		return NewOAuthService(repo, auth.NewTokenIssuer(jwtConfig, 5*time.Minute), pepper), repo
+	}
+
+	t.Run("Issued token is accepted by the verifier", func(t *testing.T) {
+		service, _ := newService()
+
+		token, err := service.IssueClientCredentialsToken(ctx, "cli_reconciliation", "correct-secret", []string{"status:read"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer", token.TokenType)
+		assert.Equal(t, 300, token.ExpiresIn)
+		assert.Equal(t, "status:read", token.Scope)
+
+		principal, err := auth.NewJWTVerifier(jwtConfig).Verify(ctx, token.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "cli_reconciliation", principal.Subject)
+		assert.Equal(t, []string{"status:read"}, principal.Scopes)
+	})
+
+	t.Run("Issued token is limited by the client's tier", func(t *testing.T) {
+		service, repo := newService()
+		token, err := service.IssueClientCredentialsToken(ctx, "cli_reconciliation", "correct-secret", nil)
+		require.NoError(t, err)
+
+		tiered := jwtConfig
+		tiered.Tiers = service
+		principal, err := auth.NewJWTVerifier(tiered).Verify(ctx, token.AccessToken)
+
+		require.NoError(t, err)
+		assert.Equal(t, "batch", principal.Tier)
+
+		// Subjects of other issuers are never looked up
+		tier, err := service.TierFor(ctx, "payroll-batch")
+		require.NoError(t, err)
+		assert.Empty(t, tier)
+		repo.AssertNotCalled(t, "GetByClientID", ctx, "payroll-batch")
+	})
+
+	t.Run("No requested scopes grants all allowed scopes", func(t *testing.T) {
+		service, _ := newService()
+
+		token, err := service.IssueClientCredentialsToken(ctx, "cli_reconciliation", "correct-secret", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "status:read pii:read", token.Scope)
+	})
+
+	t.Run("Scope outside the client's grant", func(t *testing.T) {
+		service, _ := newService()
+
+		_, err := service.IssueClientCredentialsToken(ctx, "cli_reconciliation", "correct-secret", []string{"admin"})
+
+		oauthErr, ok := err.(*OAuthError)
+		require.True(t, ok)
+		assert.Equal(t, 400, oauthErr.Status)
+		assert.Equal(t, OAuthInvalidScope, oauthErr.Code)
+	})
+
+	t.Run("Wrong secret and unknown client look the same", func(t *testing.T) {
+		service, _ := newService()
+
+		_, wrongSecret := service.IssueClientCredentialsToken(ctx, "cli_reconciliation", "wrong-secret", nil)
+		_, unknown := service.IssueClientCredentialsToken(ctx, "cli_unknown", "correct-secret", nil)
+
+		assert.Equal(t, wrongSecret, unknown)
+		oauthErr, ok := wrongSecret.(*OAuthError)
+		require.True(t, ok)
+		assert.Equal(t, 401, oauthErr.Status)
+		assert.Equal(t, OAuthInvalidClient, oauthErr.Code)
+	})
+
+	t.Run("Inactive client", func(t *testing.T) {
+		repo := new(MockOAuthClientRepository)
+		inactive := *client
+		inactive.IsActive = false
+		repo.On("GetByClientID", ctx, "cli_reconciliation").Return(&inactive, nil)
+		service := NewOAuthService(repo, auth.NewTokenIssuer(jwtConfig, 5*time.Minute), pepper)
+
+		_, err := service.IssueClientCredentialsToken(ctx, "cli_reconciliation", "correct-secret", nil)
+
+		oauthErr, ok := err.(*OAuthError)
+		require.True(t, ok)
+		assert.Equal(t, OAuthInvalidClient, oauthErr.Code)
+	})
+}
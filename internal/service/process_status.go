@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,50 +10,116 @@ import (
 	"github.com/rixtrayker/getemps-service/internal/cache"
 	"github.com/rixtrayker/getemps-service/internal/models"
 	"github.com/rixtrayker/getemps-service/internal/repository"
+	"github.com/sirupsen/logrus"
 )
 
 type ProcessStatusService struct {
-	userRepo   repository.UserRepository
-	salaryRepo repository.SalaryRepository
-	calculator *SalaryCalculator
-	cache      cache.Cache
-	cacheTTL   time.Duration
+	userRepo    repository.UserRepository
+	salaryRepo  repository.SalaryRepository
+	auditRepo   repository.AccessAuditRepository
+	rateRepo    repository.ExchangeRateRepository
+	historyRepo repository.StatusHistoryRepository
+	rankingRepo repository.SalaryRankingRepository
+	calculator  *SalaryCalculator
+	cache       cache.Cache
+	cacheTTL    time.Duration
+	// windowMonths is the default calculation window, zero for full history
+	windowMonths int
+	sufficiency  SufficiencyPolicy
+	logger       *logrus.Logger
+	now          func() time.Time
 }
 
 func NewProcessStatusService(
 	userRepo repository.UserRepository,
 	salaryRepo repository.SalaryRepository,
+	auditRepo repository.AccessAuditRepository,
+	calculator *SalaryCalculator,
 	cache cache.Cache,
 	cacheTTL time.Duration,
 ) *ProcessStatusService {
 	return &ProcessStatusService{
-		userRepo:   userRepo,
-		salaryRepo: salaryRepo,
-		calculator: NewSalaryCalculator(),
-		cache:      cache,
-		cacheTTL:   cacheTTL,
+		userRepo:    userRepo,
+		salaryRepo:  salaryRepo,
+		auditRepo:   auditRepo,
+		calculator:  calculator,
+		cache:       cache,
+		cacheTTL:    cacheTTL,
+		sufficiency: DefaultSufficiencyPolicy(),
+		now:         time.Now,
 	}
 }
 
+// SetLogger reports failures of optional steps, such as the percentile rank,
+// that do not fail a lookup.
+func (s *ProcessStatusService) SetLogger(logger *logrus.Logger) {
+	s.logger = logger
+}
+
+// SetSufficiencyPolicy replaces the default requirement of three salary
+// records.
+func (s *ProcessStatusService) SetSufficiencyPolicy(policy SufficiencyPolicy) {
+	s.sufficiency = policy
+}
+
+// SetExchangeRateRepository supplies the rates used to convert salaries paid
+// in other currencies. Without it such salaries cannot be calculated.
+func (s *ProcessStatusService) SetExchangeRateRepository(rateRepo repository.ExchangeRateRepository) {
+	s.rateRepo = rateRepo
+}
+
+// SetStatusHistoryRepository enables recording a snapshot whenever a status
+// computed over the default window differs from the last one recorded.
+func (s *ProcessStatusService) SetStatusHistoryRepository(historyRepo repository.StatusHistoryRepository) {
+	s.historyRepo = historyRepo
+}
+
+// SetSalaryRankingRepository adds the employee's percentile rank among active
+// employees to lookups over the default window.
+func (s *ProcessStatusService) SetSalaryRankingRepository(rankingRepo repository.SalaryRankingRepository) {
+	s.rankingRepo = rankingRepo
+}
+
+// SetDefaultWindow limits lookups that do not ask for a window to the
+// trailing months, including the current one. Zero uses the full history.
+func (s *ProcessStatusService) SetDefaultWindow(months int) {
+	s.windowMonths = months
+}
+
+// GetEmployeeStatus looks up an employee's salary status. Every lookup,
+// including cache hits and failures, is recorded in the access audit trail.
 func (s *ProcessStatusService) GetEmployeeStatus(ctx context.Context, nationalNumber string) (*models.EmployeeInfo, error) {
+	return s.GetEmployeeStatusWithOptions(ctx, nationalNumber, StatusOptions{})
+}
+
+// GetEmployeeStatusWithOptions is GetEmployeeStatus with optional extras such
+// as the calculation breakdown.
+func (s *ProcessStatusService) GetEmployeeStatusWithOptions(ctx context.Context, nationalNumber string, opts StatusOptions) (*models.EmployeeInfo, error) {
+	employeeInfo, err := s.lookupEmployeeStatus(ctx, nationalNumber, opts)
+
+	if auditErr := recordAccess(ctx, s.auditRepo, nationalNumber, err); auditErr != nil && err == nil {
+		// Salary data is not released without an audit record
+		return nil, auditErr
+	}
+
+	return employeeInfo, err
+}
+
+func (s *ProcessStatusService) lookupEmployeeStatus(ctx context.Context, nationalNumber string, opts StatusOptions) (*models.EmployeeInfo, error) {
+	window := opts.Window.resolve(s.now(), s.windowMonths)
+	cacheKey := window.cacheKey(opts.cacheKey(cache.GenerateCacheKey(nationalNumber)))
+
 	// Step 1: Check cache first
 	if s.cache != nil {
-		cacheKey := cache.GenerateCacheKey(nationalNumber)
 		if cachedResult, found := s.cache.Get(cacheKey); found {
 			return cachedResult, nil
 		}
 	}
 
 	// Step 2: Validate and fetch user
-	user, err := s.userRepo.GetByNationalNumber(ctx, nationalNumber)
+	user, err := findUser(ctx, s.userRepo, nationalNumber)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			return nil, &AppError{
-				Code:    404,
-				Message: "Invalid National Number",
-			}
-		}
-		return nil, fmt.Errorf("failed to fetch user: %w", err)
+		return nil, err
 	}
 
 	// Step 3: Check if user is active
@@ -63,29 +130,15 @@ func (s *ProcessStatusService) GetEmployeeStatus(ctx context.Context, nationalNu
 		}
 	}
 
-	// Step 4: Check salary records count
-	salaryCount, err := s.salaryRepo.CountByUserID(ctx, user.ID)
+	// Steps 4-6: Check and fetch salary records, then calculate salary
+	// statistics and status
+	calculation, err := s.calculate(ctx, user.ID, window)
 	if err != nil {
-		return nil, fmt.Errorf("failed to count salary records: %w", err)
+		return nil, err
 	}
 
-	if salaryCount < 3 {
-		return nil, &AppError{
-			Code:    422,
-			Message: "INSUFFICIENT_DATA",
-		}
-	}
-
-	// Step 5: Fetch salary records
-	salaries, err := s.salaryRepo.GetByUserID(ctx, user.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch salary records: %w", err)
-	}
-
-	// Step 6: Calculate salary statistics and status
-	calculation := s.calculator.CalculateEmployeeStatus(salaries)
-
 	// Step 7: Build response
+	computedAt := s.now()
 	employeeInfo := &models.EmployeeInfo{
 		ID:             user.ID,
 		Username:       user.Username,
@@ -93,29 +146,209 @@ func (s *ProcessStatusService) GetEmployeeStatus(ctx context.Context, nationalNu
 		Email:          user.Email,
 		Phone:          user.Phone,
 		IsActive:       user.IsActive,
-		SalaryDetails: models.SalaryDetails{
-			AverageSalary: calculation.AverageSalary,
-			HighestSalary: calculation.HighestSalary,
-			SumOfSalaries: calculation.SumOfSalaries,
-		},
-		Status:      calculation.Status,
-		LastUpdated: time.Now(),
+		SalaryDetails:  salaryDetails(calculation),
+		Status:         calculation.Status,
+		RuleVersions:   calculation.RuleVersions,
+		Trend:          trendDetails(calculation.Trend),
+		LastUpdated:    computedAt,
+	}
+	if opts.Explain {
+		employeeInfo.Explanation = buildExplanation(calculation)
+	}
+	// Rankings are made over the default window, so they do not describe
+	// other windows
+	if s.rankingRepo != nil && opts.Window == (Window{}) {
+		employeeInfo.PercentileRank = s.percentileRank(ctx, user.ID)
+	}
+
+	// Step 8: Record the status history. Lookups over a requested window are
+	// what-ifs rather than the employee's standing status.
+	if s.historyRepo != nil && opts.Window == (Window{}) {
+		s.recordSnapshot(ctx, &models.StatusSnapshot{
+			UserID:        user.ID,
+			Status:        calculation.Status,
+			AverageSalary: employeeInfo.SalaryDetails.AverageSalary,
+			HighestSalary: employeeInfo.SalaryDetails.HighestSalary,
+			SumOfSalaries: employeeInfo.SalaryDetails.SumOfSalaries,
+			Currency:      calculation.Currency,
+			RuleVersion:   calculation.RuleVersion,
+			ComputedAt:    computedAt,
+		})
 	}
 
-	// Step 8: Cache the result
+	// Step 9: Cache the result
 	if s.cache != nil {
-		cacheKey := cache.GenerateCacheKey(nationalNumber)
 		s.cache.Set(cacheKey, employeeInfo, s.cacheTTL)
 	}
 
 	return employeeInfo, nil
 }
 
+// calculate checks that an employee has sufficient salary data in the window
+// and calculates their status from it.
+func (s *ProcessStatusService) calculate(ctx context.Context, userID int64, window *periodRange) (*SalaryCalculationResult, error) {
+	salaryCount, err := s.countSalaries(ctx, userID, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count salary records: %w", err)
+	}
+
+	if err := s.sufficiency.checkCount(salaryCount); err != nil {
+		return nil, err
+	}
+
+	salaries, err := s.fetchSalaries(ctx, userID, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch salary records: %w", err)
+	}
+
+	asOf := models.PeriodOf(s.now())
+	if window != nil {
+		asOf = window.to
+	}
+	if err := s.sufficiency.check(salaries, asOf); err != nil {
+		return nil, err
+	}
+
+	rates, err := s.fetchExchangeRates(ctx, salaries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+
+	calculation, err := s.calculator.CalculateEmployeeStatusWithRates(salaries, rates)
+	if err != nil {
+		// A missing rate is a data problem, refused like insufficient data
+		var missing *MissingExchangeRateError
+		if errors.As(err, &missing) {
+			return nil, &AppError{
+				Code:    422,
+				Message: "MISSING_EXCHANGE_RATE",
+				Details: models.MissingExchangeRateDetails{
+					Currency: missing.Currency,
+					Period:   missing.Period,
+				},
+			}
+		}
+		return nil, fmt.Errorf("failed to convert salaries to %s: %w", s.calculator.Currency(), err)
+	}
+	return calculation, nil
+}
+
+// recordSnapshot adds a snapshot unless the status and the figures it came from
+// match the latest one, so the history grows with changes rather than with
+// lookups. Recording is best-effort: failures are logged, not returned.
+func (s *ProcessStatusService) recordSnapshot(ctx context.Context, snapshot *models.StatusSnapshot) {
+	latest, err := s.historyRepo.ListByUserID(ctx, snapshot.UserID, 1)
+	if err == nil && len(latest) > 0 && sameSnapshot(latest[0], *snapshot) {
+		return
+	}
+	if err == nil {
+		err = s.historyRepo.Create(ctx, snapshot)
+	}
+	if err != nil && s.logger != nil {
+		s.logger.WithError(err).WithField("user_id", snapshot.UserID).Warn("Failed to record status history")
+	}
+}
+
+// sameSnapshot reports whether two snapshots record the same status from the
+// same figures, whenever they were computed.
+func sameSnapshot(a, b models.StatusSnapshot) bool {
+	return a.Status == b.Status &&
+		a.AverageSalary == b.AverageSalary &&
+		a.HighestSalary == b.HighestSalary &&
+		a.SumOfSalaries == b.SumOfSalaries &&
+		a.Currency == b.Currency &&
+		a.RuleVersion == b.RuleVersion
+}
+
+// statusUnavailable reports whether a calculation failed because the employee
+// has no status to give, such as with insufficient salary data or a missing
+// exchange rate, rather than because of a fault.
+func statusUnavailable(err error) bool {
+	var appErr *AppError
+	return errors.As(err, &appErr)
+}
+
+// percentileRank looks up an employee's place in the latest ranking. The rank
+// is optional: employees not yet ranked have none, and a failed lookup is
+// logged rather than failing the status.
+func (s *ProcessStatusService) percentileRank(ctx context.Context, userID int64) *models.PercentileRank {
+	rank, err := s.rankingRepo.GetPercentileRank(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) && s.logger != nil {
+			s.logger.WithError(err).WithField("user_id", userID).Warn("Failed to fetch percentile rank")
+		}
+		return nil
+	}
+	return rank
+}
+
+func (s *ProcessStatusService) countSalaries(ctx context.Context, userID int64, window *periodRange) (int, error) {
+	if window == nil {
+		return s.salaryRepo.CountByUserID(ctx, userID)
+	}
+	return s.salaryRepo.CountByUserIDInRange(ctx, userID, window.from, window.to)
+}
+
+func (s *ProcessStatusService) fetchSalaries(ctx context.Context, userID int64, window *periodRange) ([]models.Salary, error) {
+	if window == nil {
+		return s.salaryRepo.GetByUserID(ctx, userID)
+	}
+	return s.salaryRepo.GetByUserIDInRange(ctx, userID, window.from, window.to)
+}
+
+// fetchExchangeRates loads the rates needed for salaries paid in other
+// currencies than the reporting one.
+func (s *ProcessStatusService) fetchExchangeRates(ctx context.Context, salaries []models.Salary) (ExchangeRates, error) {
+	currencies, from, to := foreignCurrencies(salaries, s.calculator.Currency())
+	if len(currencies) == 0 || s.rateRepo == nil {
+		return nil, nil
+	}
+
+	rates, err := s.rateRepo.GetRates(ctx, currencies, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return NewExchangeRates(rates), nil
+}
+
+func salaryDetails(calculation *SalaryCalculationResult) models.SalaryDetails {
+	return models.SalaryDetails{
+		AverageSalary:     calculation.AverageSalary.InexactFloat64(),
+		HighestSalary:     calculation.HighestSalary.InexactFloat64(),
+		SumOfSalaries:     calculation.SumOfSalaries.InexactFloat64(),
+		LowestSalary:      calculation.LowestSalary.InexactFloat64(),
+		MedianSalary:      calculation.MedianSalary.InexactFloat64(),
+		StandardDeviation: calculation.StandardDeviation.InexactFloat64(),
+		Percentile25:      calculation.Percentile25.InexactFloat64(),
+		Percentile75:      calculation.Percentile75.InexactFloat64(),
+		RecordCount:       calculation.RecordCount,
+		FirstPeriod:       calculation.FirstPeriod,
+		LastPeriod:        calculation.LastPeriod,
+		Currency:          calculation.Currency,
+	}
+}
+
+func findUser(ctx context.Context, userRepo repository.UserRepository, nationalNumber string) (*models.User, error) {
+	user, err := userRepo.GetByNationalNumber(ctx, nationalNumber)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &AppError{
+				Code:    404,
+				Message: "Invalid National Number",
+			}
+		}
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	return user, nil
+}
+
 type AppError struct {
 	Code    int    `json:"-"`
 	Message string `json:"error"`
+	// Details is optional structured context returned with the error
+	Details interface{} `json:"details,omitempty"`
 }
 
 func (e *AppError) Error() string {
 	return e.Message
-}
\ No newline at end of file
+}
@@ -3,10 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -24,6 +27,11 @@ func (m *MockUserRepository) GetByNationalNumber(ctx context.Context, nationalNu
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) ListActive(ctx context.Context) ([]models.User, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 // MockSalaryRepository is a mock implementation of SalaryRepository
 type MockSalaryRepository struct {
 	mock.Mock
@@ -39,6 +47,99 @@ func (m *MockSalaryRepository) CountByUserID(ctx context.Context, userID int64)
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockSalaryRepository) GetByUserIDInRange(ctx context.Context, userID int64, from, to models.Period) ([]models.Salary, error) {
+	args := m.Called(ctx, userID, from, to)
+	return args.Get(0).([]models.Salary), args.Error(1)
+}
+
+func (m *MockSalaryRepository) CountByUserIDInRange(ctx context.Context, userID int64, from, to models.Period) (int, error) {
+	args := m.Called(ctx, userID, from, to)
+	return args.Int(0), args.Error(1)
+}
+
+// MockAccessAuditRepository is a mock implementation of AccessAuditRepository
+type MockAccessAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccessAuditRepository) Create(ctx context.Context, entry *models.AccessAudit) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAccessAuditRepository) List(ctx context.Context, query models.AccessAuditQuery) ([]models.AccessAudit, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]models.AccessAudit), args.Error(1)
+}
+
+// MockExchangeRateRepository is a mock implementation of ExchangeRateRepository
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) GetRates(ctx context.Context, currencies []string, from, to models.Period) ([]models.ExchangeRate, error) {
+	args := m.Called(ctx, currencies, from, to)
+	return args.Get(0).([]models.ExchangeRate), args.Error(1)
+}
+
+// MockStatusHistoryRepository is a mock implementation of StatusHistoryRepository
+type MockStatusHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockStatusHistoryRepository) Create(ctx context.Context, snapshot *models.StatusSnapshot) error {
+	args := m.Called(ctx, snapshot)
+	return args.Error(0)
+}
+
+func (m *MockStatusHistoryRepository) ListByUserID(ctx context.Context, userID int64, limit int) ([]models.StatusSnapshot, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]models.StatusSnapshot), args.Error(1)
+}
+
+// MockSalaryRankingRepository is a mock implementation of SalaryRankingRepository
+type MockSalaryRankingRepository struct {
+	mock.Mock
+}
+
+func (m *MockSalaryRankingRepository) Replace(ctx context.Context, rankings []models.SalaryRanking) error {
+	args := m.Called(ctx, rankings)
+	return args.Error(0)
+}
+
+func (m *MockSalaryRankingRepository) GetPercentileRank(ctx context.Context, userID int64) (*models.PercentileRank, error) {
+	args := m.Called(ctx, userID)
+	rank, _ := args.Get(0).(*models.PercentileRank)
+	return rank, args.Error(1)
+}
+
+func (m *MockSalaryRankingRepository) ListByUserIDs(ctx context.Context, userIDs []int64) ([]models.SalaryRanking, error) {
+	args := m.Called(ctx, userIDs)
+	rankings, _ := args.Get(0).([]models.SalaryRanking)
+	return rankings, args.Error(1)
+}
+
+// MockDepartmentRepository is a mock implementation of DepartmentRepository
+type MockDepartmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockDepartmentRepository) ListSubtree(ctx context.Context, departmentID int64) ([]models.Department, error) {
+	args := m.Called(ctx, departmentID)
+	departments, _ := args.Get(0).([]models.Department)
+	return departments, args.Error(1)
+}
+
+func (m *MockDepartmentRepository) ListActiveMembers(ctx context.Context, departmentIDs []int64) ([]models.User, error) {
+	args := m.Called(ctx, departmentIDs)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockDepartmentRepository) ListActiveReports(ctx context.Context, managerID int64) ([]models.User, error) {
+	args := m.Called(ctx, managerID)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 // MockCache is a mock implementation of Cache
 type MockCache struct {
 	mock.Mock
@@ -67,6 +168,8 @@ func TestProcessStatusService_GetEmployeeStatus_WithCache(t *testing.T) {
 	service := NewProcessStatusService(
 		mockUserRepo,
 		mockSalaryRepo,
+		nil,
+		NewSalaryCalculator(),
 		mockCache,
 		5*time.Minute,
 	)
@@ -130,9 +233,9 @@ func TestProcessStatusService_GetEmployeeStatus_WithCache(t *testing.T) {
 		}
 
 		salaries := []models.Salary{
-			{ID: 1, Year: 2024, Month: 1, Salary: 1000.0, UserID: 1, CreatedAt: time.Now()},
-			{ID: 2, Year: 2024, Month: 2, Salary: 1500.0, UserID: 1, CreatedAt: time.Now()},
-			{ID: 3, Year: 2024, Month: 3, Salary: 2000.0, UserID: 1, CreatedAt: time.Now()},
+			{ID: 1, Year: 2024, Month: 1, Salary: decimal.NewFromFloat(1000.0), UserID: 1, CreatedAt: time.Now()},
+			{ID: 2, Year: 2024, Month: 2, Salary: decimal.NewFromFloat(1500.0), UserID: 1, CreatedAt: time.Now()},
+			{ID: 3, Year: 2024, Month: 3, Salary: decimal.NewFromFloat(2000.0), UserID: 1, CreatedAt: time.Now()},
 		}
 
 		// Mock expectations
@@ -278,6 +381,8 @@ func TestProcessStatusService_GetEmployeeStatus_WithoutCache(t *testing.T) {
 	service := NewProcessStatusService(
 		mockUserRepo,
 		mockSalaryRepo,
+		nil,
+		NewSalaryCalculator(),
 		nil, // No cache
 		5*time.Minute,
 	)
@@ -299,9 +404,9 @@ func TestProcessStatusService_GetEmployeeStatus_WithoutCache(t *testing.T) {
 		}
 
 		salaries := []models.Salary{
-			{ID: 1, Year: 2024, Month: 1, Salary: 2500.0, UserID: 2, CreatedAt: time.Now()},
-			{ID: 2, Year: 2024, Month: 2, Salary: 2500.0, UserID: 2, CreatedAt: time.Now()},
-			{ID: 3, Year: 2024, Month: 3, Salary: 2500.0, UserID: 2, CreatedAt: time.Now()},
+			{ID: 1, Year: 2024, Month: 1, Salary: decimal.NewFromFloat(2500.0), UserID: 2, CreatedAt: time.Now()},
+			{ID: 2, Year: 2024, Month: 2, Salary: decimal.NewFromFloat(2500.0), UserID: 2, CreatedAt: time.Now()},
+			{ID: 3, Year: 2024, Month: 3, Salary: decimal.NewFromFloat(2500.0), UserID: 2, CreatedAt: time.Now()},
 		}
 
 		// Mock expectations
@@ -339,6 +444,8 @@ func TestProcessStatusService_CacheKeyGeneration(t *testing.T) {
 	service := NewProcessStatusService(
 		mockUserRepo,
 		mockSalaryRepo,
+		nil,
+		NewSalaryCalculator(),
 		mockCache,
 		5*time.Minute,
 	)
@@ -394,6 +501,8 @@ func TestProcessStatusService_CacheIntegration_BusinessLogic(t *testing.T) {
 	service := NewProcessStatusService(
 		mockUserRepo,
 		mockSalaryRepo,
+		nil,
+		NewSalaryCalculator(),
 		mockCache,
 		5*time.Minute,
 	)
@@ -417,9 +526,9 @@ func TestProcessStatusService_CacheIntegration_BusinessLogic(t *testing.T) {
 
 		// Setup salaries with seasonal adjustments and tax implications
 		salaries := []models.Salary{
-			{ID: 1, Year: 2024, Month: 6, Salary: 5000.0, UserID: 5, CreatedAt: time.Now()},  // Summer month (-5%)
-			{ID: 2, Year: 2024, Month: 12, Salary: 5000.0, UserID: 5, CreatedAt: time.Now()}, // December (+10%)
-			{ID: 3, Year: 2024, Month: 3, Salary: 5000.0, UserID: 5, CreatedAt: time.Now()},  // Regular month
+			{ID: 1, Year: 2024, Month: 6, Salary: decimal.NewFromFloat(5000.0), UserID: 5, CreatedAt: time.Now()},  // Summer month (-5%)
+			{ID: 2, Year: 2024, Month: 12, Salary: decimal.NewFromFloat(5000.0), UserID: 5, CreatedAt: time.Now()}, // December (+10%)
+			{ID: 3, Year: 2024, Month: 3, Salary: decimal.NewFromFloat(5000.0), UserID: 5, CreatedAt: time.Now()},  // Regular month
 		}
 
 		// Mock expectations
@@ -464,6 +573,8 @@ func TestProcessStatusService_ErrorHandling(t *testing.T) {
 	service := NewProcessStatusService(
 		mockUserRepo,
 		mockSalaryRepo,
+		nil,
+		NewSalaryCalculator(),
 		mockCache,
 		5*time.Minute,
 	)
@@ -516,4 +627,427 @@ func TestProcessStatusService_ErrorHandling(t *testing.T) {
 		mockUserRepo.AssertExpectations(t)
 		mockSalaryRepo.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+
+func TestProcessStatusService_AccessAudit(t *testing.T) {
+	info := RequestInfo{
+		Caller:     "payroll-portal",
+		AuthMethod: "jwt",
+		ClientIP:   "10.0.0.7",
+		RequestID:  "req-1",
+	}
+	ctx := WithRequestInfo(context.Background(), info)
+
+	newService := func() (*ProcessStatusService, *MockUserRepository, *MockCache, *MockAccessAuditRepository) {
+		mockUserRepo := new(MockUserRepository)
+		mockCache := new(MockCache)
+		mockAuditRepo := new(MockAccessAuditRepository)
+		service := NewProcessStatusService(mockUserRepo, new(MockSalaryRepository), mockAuditRepo, NewSalaryCalculator(), mockCache, 5*time.Minute)
+		return service, mockUserRepo, mockCache, mockAuditRepo
+	}
+
+	auditEntry := func(resultCode int) *models.AccessAudit {
+		return &models.AccessAudit{
+			Caller:         info.Caller,
+			AuthMethod:     info.AuthMethod,
+			NationalNumber: "NAT1001",
+			ResultCode:     resultCode,
+			ClientIP:       info.ClientIP,
+			RequestID:      info.RequestID,
+		}
+	}
+
+	t.Run("Cache hit is audited", func(t *testing.T) {
+		service, _, mockCache, mockAuditRepo := newService()
+		cached := &models.EmployeeInfo{ID: 1, NationalNumber: "NAT1001", Status: "GREEN"}
+
+		mockCache.On("Get", "emp_status:NAT1001").Return(cached, true)
+		mockAuditRepo.On("Create", ctx, auditEntry(200)).Return(nil)
+
+		result, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		assert.Equal(t, cached, result)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed lookup is audited with its result code", func(t *testing.T) {
+		service, mockUserRepo, mockCache, mockAuditRepo := newService()
+
+		mockCache.On("Get", "emp_status:NAT1001").Return((*models.EmployeeInfo)(nil), false)
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return((*models.User)(nil), errors.New("user not found"))
+		mockAuditRepo.On("Create", ctx, auditEntry(404)).Return(nil)
+
+		_, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		var appErr *AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 404, appErr.Code)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Audit failure withholds the result", func(t *testing.T) {
+		service, _, mockCache, mockAuditRepo := newService()
+		cached := &models.EmployeeInfo{ID: 1, NationalNumber: "NAT1001", Status: "GREEN"}
+
+		mockCache.On("Get", "emp_status:NAT1001").Return(cached, true)
+		mockAuditRepo.On("Create", ctx, auditEntry(200)).Return(errors.New("audit table unavailable"))
+
+		result, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestProcessStatusService_Explain(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockSalaryRepo := new(MockSalaryRepository)
+	mockCache := new(MockCache)
+	service := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), mockCache, 5*time.Minute)
+
+	ctx := context.Background()
+	user := &models.User{ID: 1, Username: "test_user", NationalNumber: "NAT1001", IsActive: true}
+	salaries := []models.Salary{
+		{Year: 2024, Month: 1, Salary: decimal.NewFromFloat(1200)},
+		{Year: 2024, Month: 6, Salary: decimal.NewFromFloat(1600)},
+		{Year: 2024, Month: 12, Salary: decimal.NewFromFloat(2000)},
+	}
+
+	// Explained results are cached separately from plain ones
+	mockCache.On("Get", "emp_status:NAT1001:explain").Return((*models.EmployeeInfo)(nil), false)
+	mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return(user, nil)
+	mockSalaryRepo.On("CountByUserID", ctx, user.ID).Return(len(salaries), nil)
+	mockSalaryRepo.On("GetByUserID", ctx, user.ID).Return(salaries, nil)
+	mockCache.On("Set", "emp_status:NAT1001:explain", mock.AnythingOfType("*models.EmployeeInfo"), 5*time.Minute).Return()
+
+	result, err := service.GetEmployeeStatusWithOptions(ctx, "NAT1001", StatusOptions{Explain: true})
+
+	require.NoError(t, err)
+	require.NotNil(t, result.Explanation)
+	explanation := result.Explanation
+
+	assert.Equal(t, "default", explanation.RuleVersion)
+	require.Len(t, explanation.Months, 3)
+	assert.Equal(t, models.MonthExplanation{
+		Year:         2024,
+		Month:        6,
+		RuleVersion:  "default",
+		Original:     1600,
+		Currency:     "JOD",
+		ExchangeRate: 1,
+		Converted:    1600,
+		Adjustments:  []string{"Summer deduction"},
+		Multiplier:   0.95,
+		Adjusted:     1520,
+		Tax:          0,
+		Final:        1520,
+	}, explanation.Months[1])
+	assert.Equal(t, []string{}, explanation.Months[0].Adjustments)
+	assert.Equal(t, 2200.0, explanation.Months[2].Final)
+	assert.Equal(t, 4920.0, explanation.TotalBeforeTax)
+	assert.Equal(t, "flat", explanation.Tax.Mode)
+	assert.Equal(t, 0.0, explanation.Tax.TotalTax)
+	assert.Equal(t, "average 1640 < threshold 2000 => RED", explanation.Comparison)
+
+	mockCache.AssertExpectations(t)
+}
+
+func TestProcessStatusService_Window(t *testing.T) {
+	ctx := context.Background()
+	user := &models.User{ID: 1, Username: "test_user", NationalNumber: "NAT1001", IsActive: true}
+	recent := []models.Salary{
+		{Year: 2026, Month: 2, Salary: decimal.NewFromFloat(2500)},
+		{Year: 2026, Month: 3, Salary: decimal.NewFromFloat(2500)},
+		{Year: 2026, Month: 4, Salary: decimal.NewFromFloat(2500)},
+	}
+	now := time.Date(2026, time.April, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Explicit range", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockSalaryRepo := new(MockSalaryRepository)
+		mockCache := new(MockCache)
+		service := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), mockCache, 5*time.Minute)
+
+		from := models.Period{Year: 2026, Month: 2}
+		to := models.Period{Year: 2026, Month: 4}
+		mockCache.On("Get", "emp_status:NAT1001:2026-02:2026-04").Return((*models.EmployeeInfo)(nil), false)
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return(user, nil)
+		mockSalaryRepo.On("CountByUserIDInRange", ctx, user.ID, from, to).Return(len(recent), nil)
+		mockSalaryRepo.On("GetByUserIDInRange", ctx, user.ID, from, to).Return(recent, nil)
+		mockCache.On("Set", "emp_status:NAT1001:2026-02:2026-04", mock.AnythingOfType("*models.EmployeeInfo"), 5*time.Minute).Return()
+
+		result, err := service.GetEmployeeStatusWithOptions(ctx, "NAT1001", StatusOptions{
+			Window: Window{From: from, To: to},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusGreen, result.Status)
+		mockSalaryRepo.AssertNotCalled(t, "GetByUserID", ctx, user.ID)
+		mockCache.AssertExpectations(t)
+	})
+
+	t.Run("Default trailing window crosses the year", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockSalaryRepo := new(MockSalaryRepository)
+		service := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		service.SetDefaultWindow(6)
+		service.now = func() time.Time { return now }
+
+		from := models.Period{Year: 2025, Month: 11}
+		to := models.Period{Year: 2026, Month: 4}
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return(user, nil)
+		mockSalaryRepo.On("CountByUserIDInRange", ctx, user.ID, from, to).Return(len(recent), nil)
+		mockSalaryRepo.On("GetByUserIDInRange", ctx, user.ID, from, to).Return(recent, nil)
+
+		_, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		mockSalaryRepo.AssertExpectations(t)
+	})
+
+	t.Run("Too few salaries inside the window", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockSalaryRepo := new(MockSalaryRepository)
+		service := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		service.now = func() time.Time { return now }
+
+		from := models.Period{Year: 2026, Month: 3}
+		to := models.Period{Year: 2026, Month: 4}
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return(user, nil)
+		mockSalaryRepo.On("CountByUserIDInRange", ctx, user.ID, from, to).Return(2, nil)
+
+		_, err := service.GetEmployeeStatusWithOptions(ctx, "NAT1001", StatusOptions{
+			Window: Window{LastMonths: 2},
+		})
+
+		var appErr *AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 422, appErr.Code)
+	})
+}
+
+func TestProcessStatusService_Currency(t *testing.T) {
+	ctx := context.Background()
+	user := &models.User{ID: 1, Username: "test_user", NationalNumber: "NAT1001", IsActive: true}
+	salaries := []models.Salary{
+		{Year: 2024, Month: 1, Salary: decimal.NewFromFloat(2000), Currency: "JOD"},
+		{Year: 2024, Month: 2, Salary: decimal.NewFromFloat(3000), Currency: "USD"},
+		{Year: 2024, Month: 3, Salary: decimal.NewFromFloat(3000), Currency: "USD"},
+	}
+	from := models.Period{Year: 2024, Month: 2}
+	to := models.Period{Year: 2024, Month: 3}
+
+	t.Run("Foreign salaries are converted", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockSalaryRepo := new(MockSalaryRepository)
+		mockRateRepo := new(MockExchangeRateRepository)
+		service := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		service.SetExchangeRateRepository(mockRateRepo)
+
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return(user, nil)
+		mockSalaryRepo.On("CountByUserID", ctx, user.ID).Return(len(salaries), nil)
+		mockSalaryRepo.On("GetByUserID", ctx, user.ID).Return(salaries, nil)
+		mockRateRepo.On("GetRates", ctx, []string{"USD"}, from, to).Return([]models.ExchangeRate{
+			{Currency: "USD", Year: 2024, Month: 2, Rate: decimal.RequireFromString("0.709")},
+			{Currency: "USD", Year: 2024, Month: 3, Rate: decimal.RequireFromString("0.71")},
+		}, nil)
+
+		result, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		// 2000 + 2127 + 2130
+		assert.Equal(t, 6257.0, result.SalaryDetails.SumOfSalaries)
+		assert.Equal(t, 2130.0, result.SalaryDetails.HighestSalary)
+		assert.Equal(t, "JOD", result.SalaryDetails.Currency)
+		assert.Equal(t, StatusGreen, result.Status)
+	})
+
+	t.Run("Missing rate", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockSalaryRepo := new(MockSalaryRepository)
+		mockRateRepo := new(MockExchangeRateRepository)
+		service := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		service.SetExchangeRateRepository(mockRateRepo)
+
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return(user, nil)
+		mockSalaryRepo.On("CountByUserID", ctx, user.ID).Return(len(salaries), nil)
+		mockSalaryRepo.On("GetByUserID", ctx, user.ID).Return(salaries, nil)
+		mockRateRepo.On("GetRates", ctx, []string{"USD"}, from, to).Return([]models.ExchangeRate{
+			{Currency: "USD", Year: 2024, Month: 2, Rate: decimal.RequireFromString("0.709")},
+		}, nil)
+
+		_, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		var appErr *AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 422, appErr.Code)
+		assert.Equal(t, "MISSING_EXCHANGE_RATE", appErr.Message)
+		assert.Equal(t, models.MissingExchangeRateDetails{Currency: "USD", Period: to}, appErr.Details)
+	})
+}
+
+func TestProcessStatusService_StatusHistory(t *testing.T) {
+	ctx := context.Background()
+	user := &models.User{ID: 1, Username: "test_user", NationalNumber: "NAT1001", IsActive: true}
+	salaries := []models.Salary{
+		{Year: 2024, Month: 1, Salary: decimal.NewFromFloat(2500)},
+		{Year: 2024, Month: 2, Salary: decimal.NewFromFloat(2500)},
+		{Year: 2024, Month: 3, Salary: decimal.NewFromFloat(2500)},
+	}
+	now := time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC)
+
+	newService := func() (*ProcessStatusService, *MockSalaryRepository, *MockStatusHistoryRepository) {
+		mockUserRepo := new(MockUserRepository)
+		mockSalaryRepo := new(MockSalaryRepository)
+		mockHistoryRepo := new(MockStatusHistoryRepository)
+		service := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		service.SetStatusHistoryRepository(mockHistoryRepo)
+		service.now = func() time.Time { return now }
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return(user, nil)
+		return service, mockSalaryRepo, mockHistoryRepo
+	}
+
+	t.Run("Computed status is recorded", func(t *testing.T) {
+		service, mockSalaryRepo, mockHistoryRepo := newService()
+		mockSalaryRepo.On("CountByUserID", ctx, user.ID).Return(len(salaries), nil)
+		mockSalaryRepo.On("GetByUserID", ctx, user.ID).Return(salaries, nil)
+		mockHistoryRepo.On("ListByUserID", ctx, user.ID, 1).Return([]models.StatusSnapshot{}, nil)
+		mockHistoryRepo.On("Create", ctx, &models.StatusSnapshot{
+			UserID:        user.ID,
+			Status:        StatusGreen,
+			AverageSalary: 2500,
+			HighestSalary: 2500,
+			SumOfSalaries: 7500,
+			Currency:      "JOD",
+			RuleVersion:   "default",
+			ComputedAt:    now,
+		}).Return(nil)
+
+		result, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		assert.Equal(t, now, result.LastUpdated)
+		mockHistoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("Requested windows are not recorded", func(t *testing.T) {
+		service, mockSalaryRepo, mockHistoryRepo := newService()
+		from := models.Period{Year: 2024, Month: 1}
+		to := models.Period{Year: 2024, Month: 3}
+		mockSalaryRepo.On("CountByUserIDInRange", ctx, user.ID, from, to).Return(len(salaries), nil)
+		mockSalaryRepo.On("GetByUserIDInRange", ctx, user.ID, from, to).Return(salaries, nil)
+
+		_, err := service.GetEmployeeStatusWithOptions(ctx, "NAT1001", StatusOptions{
+			Window: Window{From: from, To: to},
+		})
+
+		require.NoError(t, err)
+		mockHistoryRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unchanged status is not recorded again", func(t *testing.T) {
+		service, mockSalaryRepo, mockHistoryRepo := newService()
+		mockSalaryRepo.On("CountByUserID", ctx, user.ID).Return(len(salaries), nil)
+		mockSalaryRepo.On("GetByUserID", ctx, user.ID).Return(salaries, nil)
+		mockHistoryRepo.On("ListByUserID", ctx, user.ID, 1).Return([]models.StatusSnapshot{{
+			ID:            3,
+			UserID:        user.ID,
+			Status:        StatusGreen,
+			AverageSalary: 2500,
+			HighestSalary: 2500,
+			SumOfSalaries: 7500,
+			Currency:      "JOD",
+			RuleVersion:   "default",
+			ComputedAt:    now.Add(-24 * time.Hour),
+		}}, nil)
+
+		_, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		mockHistoryRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Changed figures are recorded", func(t *testing.T) {
+		service, mockSalaryRepo, mockHistoryRepo := newService()
+		mockSalaryRepo.On("CountByUserID", ctx, user.ID).Return(len(salaries), nil)
+		mockSalaryRepo.On("GetByUserID", ctx, user.ID).Return(salaries, nil)
+		mockHistoryRepo.On("ListByUserID", ctx, user.ID, 1).Return([]models.StatusSnapshot{{
+			Status: StatusGreen, AverageSalary: 2400, HighestSalary: 2500, SumOfSalaries: 7200, Currency: "JOD", RuleVersion: "default",
+		}}, nil)
+		mockHistoryRepo.On("Create", ctx, mock.AnythingOfType("*models.StatusSnapshot")).Return(nil)
+
+		_, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		mockHistoryRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("Recording failure does not fail the lookup", func(t *testing.T) {
+		service, mockSalaryRepo, mockHistoryRepo := newService()
+		mockSalaryRepo.On("CountByUserID", ctx, user.ID).Return(len(salaries), nil)
+		mockSalaryRepo.On("GetByUserID", ctx, user.ID).Return(salaries, nil)
+		mockHistoryRepo.On("ListByUserID", ctx, user.ID, 1).Return([]models.StatusSnapshot{}, nil)
+		mockHistoryRepo.On("Create", ctx, mock.AnythingOfType("*models.StatusSnapshot")).Return(errors.New("db down"))
+
+		result, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusGreen, result.Status)
+	})
+}
+
+func TestProcessStatusService_PercentileRank(t *testing.T) {
+	ctx := context.Background()
+	user := &models.User{ID: 1, Username: "test_user", NationalNumber: "NAT1001", IsActive: true}
+	salaries := []models.Salary{
+		{Year: 2024, Month: 1, Salary: decimal.NewFromFloat(2500)},
+		{Year: 2024, Month: 2, Salary: decimal.NewFromFloat(2500)},
+		{Year: 2024, Month: 3, Salary: decimal.NewFromFloat(2500)},
+	}
+
+	newService := func() (*ProcessStatusService, *MockSalaryRankingRepository) {
+		mockUserRepo := new(MockUserRepository)
+		mockSalaryRepo := new(MockSalaryRepository)
+		mockRankingRepo := new(MockSalaryRankingRepository)
+		service := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		service.SetSalaryRankingRepository(mockRankingRepo)
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return(user, nil)
+		mockSalaryRepo.On("CountByUserID", ctx, user.ID).Return(len(salaries), nil)
+		mockSalaryRepo.On("GetByUserID", ctx, user.ID).Return(salaries, nil)
+		return service, mockRankingRepo
+	}
+
+	t.Run("Rank is added to the lookup", func(t *testing.T) {
+		service, mockRankingRepo := newService()
+		department := 50.0
+		rank := &models.PercentileRank{Overall: 75, Department: &department, Employees: 5, DepartmentEmployees: 3}
+		mockRankingRepo.On("GetPercentileRank", ctx, user.ID).Return(rank, nil)
+
+		result, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		assert.Equal(t, rank, result.PercentileRank)
+	})
+
+	t.Run("Unranked employees have no rank", func(t *testing.T) {
+		service, mockRankingRepo := newService()
+		mockRankingRepo.On("GetPercentileRank", ctx, user.ID).Return(nil, fmt.Errorf("salary ranking %w", repository.ErrNotFound))
+
+		result, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		assert.Nil(t, result.PercentileRank)
+	})
+
+	t.Run("Ranking failure leaves the rank out", func(t *testing.T) {
+		service, mockRankingRepo := newService()
+		mockRankingRepo.On("GetPercentileRank", ctx, user.ID).Return(nil, errors.New("db down"))
+
+		result, err := service.GetEmployeeStatus(ctx, "NAT1001")
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusGreen, result.Status)
+		assert.Nil(t, result.PercentileRank)
+	})
+}
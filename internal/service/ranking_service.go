@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// RankingService refreshes the stored salary ranking that percentile ranks are
+// read from, so lookups never calculate every employee's status.
+type RankingService struct {
+	userRepo    repository.UserRepository
+	rankingRepo repository.SalaryRankingRepository
+	statuses    *ProcessStatusService
+}
+
+func NewRankingService(
+	userRepo repository.UserRepository,
+	rankingRepo repository.SalaryRankingRepository,
+	statuses *ProcessStatusService,
+) *RankingService {
+	return &RankingService{
+		userRepo:    userRepo,
+		rankingRepo: rankingRepo,
+		statuses:    statuses,
+	}
+}
+
+// Refresh calculates every active employee's status over the default window,
+// exactly as a lookup would, ranks them overall and within their department
+// and replaces the stored ranking. Employees whose
+// status cannot be given, such as those with insufficient salary data, are
+// left out. It returns the number of employees ranked.
+func (s *RankingService) Refresh(ctx context.Context) (int, error) {
+	users, err := s.userRepo.ListActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshedAt := s.statuses.now()
+	window := Window{}.resolve(refreshedAt, s.statuses.windowMonths)
+
+	rankings := make([]models.SalaryRanking, 0, len(users))
+	for _, user := range users {
+		calculation, err := s.statuses.calculate(ctx, user.ID, window)
+		if statusUnavailable(err) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to rank user %d: %w", user.ID, err)
+		}
+
+		rankings = append(rankings, models.SalaryRanking{
+			UserID:         user.ID,
+			DepartmentID:   user.DepartmentID,
+			Status:         calculation.Status,
+			AverageSalary:  calculation.AverageSalary,
+			Currency:       calculation.Currency,
+			PercentileRank: models.PercentileRank{RefreshedAt: refreshedAt},
+		})
+	}
+	assignPercentiles(rankings)
+
+	if err := s.rankingRepo.Replace(ctx, rankings); err != nil {
+		return 0, err
+	}
+	return len(rankings), nil
+}
+
+// assignPercentiles ranks each employee's average salary against all ranked
+// employees and against those of the same department.
+func assignPercentiles(rankings []models.SalaryRanking) {
+	overall := make([]decimal.Decimal, 0, len(rankings))
+	byDepartment := make(map[int64][]decimal.Decimal)
+	for _, ranking := range rankings {
+		overall = append(overall, ranking.AverageSalary)
+		if ranking.DepartmentID != nil {
+			byDepartment[*ranking.DepartmentID] = append(byDepartment[*ranking.DepartmentID], ranking.AverageSalary)
+		}
+	}
+	sortAmounts(overall)
+	for _, amounts := range byDepartment {
+		sortAmounts(amounts)
+	}
+
+	for i := range rankings {
+		ranking := &rankings[i]
+		ranking.Overall = percentRank(overall, ranking.AverageSalary)
+		ranking.Employees = len(overall)
+		if ranking.DepartmentID != nil {
+			peers := byDepartment[*ranking.DepartmentID]
+			department := percentRank(peers, ranking.AverageSalary)
+			ranking.Department = &department
+			ranking.DepartmentEmployees = len(peers)
+		}
+	}
+}
+
+// percentRank is the share of the other amounts below amount, as a percentage.
+// Equal amounts share the lowest rank, like SQL PERCENT_RANK.
+func percentRank(sorted []decimal.Decimal, amount decimal.Decimal) float64 {
+	if len(sorted) < 2 {
+		return 0
+	}
+	below := sort.Search(len(sorted), func(i int) bool { return !sorted[i].LessThan(amount) })
+	return roundTo(float64(below)/float64(len(sorted)-1)*100, 2)
+}
+
+func sortAmounts(amounts []decimal.Decimal) {
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i].LessThan(amounts[j]) })
+}
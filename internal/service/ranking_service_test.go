@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankingService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC)
+	department := int64(4)
+	users := []models.User{
+		{ID: 1, NationalNumber: "NAT1001", DepartmentID: &department, IsActive: true},
+		{ID: 2, NationalNumber: "NAT1002", IsActive: true},
+		{ID: 3, NationalNumber: "NAT1003", DepartmentID: &department, IsActive: true},
+	}
+	salaries := func(amount float64) []models.Salary {
+		return []models.Salary{
+			{Year: 2024, Month: 1, Salary: decimal.NewFromFloat(amount)},
+			{Year: 2024, Month: 2, Salary: decimal.NewFromFloat(amount)},
+			{Year: 2024, Month: 3, Salary: decimal.NewFromFloat(amount)},
+		}
+	}
+
+	newService := func() (*RankingService, *MockSalaryRepository, *MockSalaryRankingRepository) {
+		mockUserRepo := new(MockUserRepository)
+		mockSalaryRepo := new(MockSalaryRepository)
+		mockRankingRepo := new(MockSalaryRankingRepository)
+		statuses := NewProcessStatusService(mockUserRepo, mockSalaryRepo, nil, NewSalaryCalculator(), nil, 5*time.Minute)
+		statuses.now = func() time.Time { return now }
+		mockUserRepo.On("ListActive", ctx).Return(users, nil)
+		return NewRankingService(mockUserRepo, mockRankingRepo, statuses), mockSalaryRepo, mockRankingRepo
+	}
+
+	t.Run("Employees with sufficient data are ranked", func(t *testing.T) {
+		service, mockSalaryRepo, mockRankingRepo := newService()
+		mockSalaryRepo.On("CountByUserID", ctx, int64(1)).Return(3, nil)
+		mockSalaryRepo.On("GetByUserID", ctx, int64(1)).Return(salaries(2500), nil)
+		mockSalaryRepo.On("CountByUserID", ctx, int64(2)).Return(2, nil)
+		mockSalaryRepo.On("CountByUserID", ctx, int64(3)).Return(3, nil)
+		mockSalaryRepo.On("GetByUserID", ctx, int64(3)).Return(salaries(1500), nil)
+		var stored []models.SalaryRanking
+		mockRankingRepo.On("Replace", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).([]models.SalaryRanking)
+		}).Return(nil)
+
+		ranked, err := service.Refresh(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, ranked)
+		require.Len(t, stored, 2)
+		assert.Equal(t, int64(1), stored[0].UserID)
+		assert.Equal(t, &department, stored[0].DepartmentID)
+		assert.Equal(t, StatusGreen, stored[0].Status)
+		assert.True(t, stored[0].AverageSalary.Equal(decimal.NewFromInt(2500)))
+		assert.Equal(t, 100.0, stored[0].Overall)
+		assert.Equal(t, 100.0, *stored[0].Department)
+		assert.Equal(t, 2, stored[0].DepartmentEmployees)
+		assert.Equal(t, now, stored[0].RefreshedAt)
+		assert.Equal(t, 0.0, stored[1].Overall)
+	})
+
+	t.Run("Database failure keeps the previous ranking", func(t *testing.T) {
+		service, mockSalaryRepo, mockRankingRepo := newService()
+		mockSalaryRepo.On("CountByUserID", ctx, int64(1)).Return(0, errors.New("db down"))
+
+		_, err := service.Refresh(ctx)
+
+		assert.Error(t, err)
+		mockRankingRepo.AssertNotCalled(t, "Replace", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAssignPercentiles(t *testing.T) {
+	sales, support := int64(1), int64(2)
+	ranking := func(userID int64, department *int64, amount int64) models.SalaryRanking {
+		return models.SalaryRanking{UserID: userID, DepartmentID: department, AverageSalary: decimal.NewFromInt(amount)}
+	}
+	rankings := []models.SalaryRanking{
+		ranking(1, &sales, 1000),
+		ranking(2, &sales, 2000),
+		ranking(3, &sales, 2000),
+		ranking(4, &support, 3000),
+		ranking(5, nil, 4000),
+	}
+
+	assignPercentiles(rankings)
+
+	overall := []float64{0, 25, 25, 75, 100}
+	for i, expected := range overall {
+		assert.Equal(t, expected, rankings[i].Overall, "user %d", rankings[i].UserID)
+		assert.Equal(t, 5, rankings[i].Employees)
+	}
+	// Ties share the lowest rank within the department too
+	assert.Equal(t, 50.0, *rankings[1].Department)
+	assert.Equal(t, 3, rankings[1].DepartmentEmployees)
+	// A department of one ranks its only employee at 0
+	assert.Equal(t, 0.0, *rankings[3].Department)
+	assert.Nil(t, rankings[4].Department)
+}
@@ -1,7 +1,12 @@
 package service
 
 import (
+	"math"
+	"sort"
+
 	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/rules"
+	"github.com/shopspring/decimal"
 )
 
 const (
@@ -10,28 +15,180 @@ const (
 	StatusRed    = "RED"
 )
 
-type SalaryCalculator struct{}
+// SalaryCalculator applies each salary month's seasonal adjustments from the
+// rule set in force for that month. Tax on the series and the status bands
+// come from the rule set in force for the latest month.
+type SalaryCalculator struct {
+	history *rules.History
+	// rules applies to the series as a whole
+	rules    *rules.RuleSet
+	currency string
+}
 
+// NewSalaryCalculator returns a calculator using the default rules.
 func NewSalaryCalculator() *SalaryCalculator {
-	return &SalaryCalculator{}
+	return NewSalaryCalculatorWithRules(rules.Default())
+}
+
+// NewSalaryCalculatorWithRules returns a calculator applying one rule set to
+// every month.
+func NewSalaryCalculatorWithRules(ruleSet *rules.RuleSet) *SalaryCalculator {
+	return NewSalaryCalculatorWithHistory(rules.SingleHistory(ruleSet))
+}
+
+// NewSalaryCalculatorWithHistory returns a calculator applying versioned
+// rule sets by effective date.
+func NewSalaryCalculatorWithHistory(history *rules.History) *SalaryCalculator {
+	return &SalaryCalculator{history: history, rules: history.Latest(), currency: DefaultCurrency}
 }
 
+// RuleVersion identifies the latest rules the calculator applies.
+func (sc *SalaryCalculator) RuleVersion() string {
+	return sc.rules.Version
+}
+
+// forSeries returns a calculator whose series-wide rules are those in force
+// for the latest salary month.
+func (sc *SalaryCalculator) forSeries(salaries []models.Salary) *SalaryCalculator {
+	latest := models.Period{Year: salaries[0].Year, Month: salaries[0].Month}
+	for _, salary := range salaries[1:] {
+		if period := (models.Period{Year: salary.Year, Month: salary.Month}); latest.Before(period) {
+			latest = period
+		}
+	}
+
+	scoped := *sc
+	scoped.rules = sc.history.At(latest.Year, latest.Month)
+	return &scoped
+}
+
+func (sc *SalaryCalculator) rulesFor(salary models.Salary) *rules.RuleSet {
+	return sc.history.At(salary.Year, salary.Month)
+}
+
+// SetCurrency sets the reporting currency salaries are converted to.
+func (sc *SalaryCalculator) SetCurrency(currency string) {
+	sc.currency = currency
+}
+
+// Currency is the reporting currency of calculated amounts.
+func (sc *SalaryCalculator) Currency() string {
+	return sc.currency
+}
+
+// Amounts are exact decimals throughout the calculation. Reported amounts are
+// rounded half away from zero to moneyPlaces; the status is decided on the
+// exact values.
+const moneyPlaces = 2
+
 type SalaryCalculationResult struct {
-	AverageSalary float64
-	HighestSalary float64
-	SumOfSalaries float64
-	Status        string
+	AverageSalary decimal.Decimal
+	HighestSalary decimal.Decimal
+	SumOfSalaries decimal.Decimal
+	// Distribution of the final amounts. The standard deviation is the
+	// population one and percentiles interpolate linearly between ranks, as
+	// spreadsheet PERCENTILE.INC does.
+	LowestSalary      decimal.Decimal
+	MedianSalary      decimal.Decimal
+	StandardDeviation decimal.Decimal
+	Percentile25      decimal.Decimal
+	Percentile75      decimal.Decimal
+	RecordCount       int
+	// FirstPeriod and LastPeriod are the months covered, nil without salaries
+	FirstPeriod *models.Period
+	LastPeriod  *models.Period
+	Status      string
+	Currency    string
+	Tax         TaxBreakdown
+	// RuleVersion is the rule set applied to the series as a whole and
+	// RuleVersions every rule set used, in effective order
+	RuleVersion  string
+	RuleVersions []string
+	// Trend is nil for fewer than two salaries
+	Trend *TrendMetrics
+	// Breakdown of the calculation, exact and unrounded
+	Months          []MonthBreakdown
+	TotalBeforeTax  decimal.Decimal
+	StatusThreshold decimal.Decimal
 }
 
+// MonthBreakdown traces one salary through the calculation. Original is in
+// the currency the salary was paid in, the other amounts in the reporting
+// currency.
+type MonthBreakdown struct {
+	Year         int
+	Month        int
+	RuleVersion  string
+	Original     decimal.Decimal
+	Currency     string
+	ExchangeRate decimal.Decimal
+	Converted    decimal.Decimal
+	Adjustments  []string
+	Multiplier   decimal.Decimal
+	Adjusted     decimal.Decimal
+	Tax          decimal.Decimal
+	Final        decimal.Decimal
+}
+
+// TaxBreakdown reports how tax was applied to the adjusted salaries.
+type TaxBreakdown struct {
+	Mode     string
+	Basis    string
+	TotalTax decimal.Decimal
+	Brackets []rules.BracketTax
+}
+
+// CalculateEmployeeStatus calculates the status of salaries that are all in
+// the reporting currency.
 func (sc *SalaryCalculator) CalculateEmployeeStatus(salaries []models.Salary) *SalaryCalculationResult {
+	exchangeRates := make([]decimal.Decimal, len(salaries))
+	for i := range salaries {
+		exchangeRates[i] = decimal.NewFromInt(1)
+	}
+	return sc.calculate(salaries, salaries, exchangeRates)
+}
+
+// CalculateEmployeeStatusWithRates converts each salary to the reporting
+// currency before calculating the status. Salaries without a currency are in
+// the reporting currency.
+func (sc *SalaryCalculator) CalculateEmployeeStatusWithRates(salaries []models.Salary, rates ExchangeRates) (*SalaryCalculationResult, error) {
+	converted := make([]models.Salary, len(salaries))
+	exchangeRates := make([]decimal.Decimal, len(salaries))
+
+	for i, salary := range salaries {
+		exchangeRates[i] = decimal.NewFromInt(1)
+		if salary.Currency != "" && salary.Currency != sc.currency {
+			period := models.Period{Year: salary.Year, Month: salary.Month}
+			rate, ok := rates[exchangeRateKey{salary.Currency, period}]
+			if !ok {
+				return nil, &MissingExchangeRateError{Currency: salary.Currency, Period: period}
+			}
+			exchangeRates[i] = rate
+		}
+
+		converted[i] = salary
+		converted[i].Salary = salary.Salary.Mul(exchangeRates[i])
+		converted[i].Currency = sc.currency
+	}
+
+	return sc.calculate(salaries, converted, exchangeRates), nil
+}
+
+// calculate runs the calculation on salaries converted to the reporting
+// currency. The original salaries are only used for the breakdown.
+func (sc *SalaryCalculator) calculate(original, salaries []models.Salary, exchangeRates []decimal.Decimal) *SalaryCalculationResult {
 	if len(salaries) == 0 {
 		return &SalaryCalculationResult{
-			AverageSalary: 0,
-			HighestSalary: 0,
-			SumOfSalaries: 0,
+			AverageSalary: decimal.Zero,
+			HighestSalary: decimal.Zero,
+			SumOfSalaries: decimal.Zero,
 			Status:        StatusRed,
+			Currency:      sc.currency,
+			RuleVersion:   sc.rules.Version,
+			RuleVersions:  []string{sc.rules.Version},
 		}
 	}
+	sc = sc.forSeries(salaries)
 
 	// Step 1: Apply seasonal adjustments
 	adjustedSalaries := sc.applySeasonalAdjustments(salaries)
@@ -40,61 +197,171 @@ func (sc *SalaryCalculator) CalculateEmployeeStatus(salaries []models.Salary) *S
 	totalBeforeTax := sc.sumSalaries(adjustedSalaries)
 
 	// Step 3: Apply tax deduction if needed
-	finalSalaries := sc.applyTaxDeduction(adjustedSalaries, totalBeforeTax)
+	finalSalaries, tax := sc.applyTax(adjustedSalaries, totalBeforeTax)
 
 	// Step 4: Calculate final statistics
-	return sc.calculateFinalStats(finalSalaries)
-}
-
-func (sc *SalaryCalculator) applySeasonalAdjustments(salaries []models.Salary) []float64 {
-	adjusted := make([]float64, len(salaries))
+	result := sc.calculateFinalStats(finalSalaries)
+	result.Currency = sc.currency
+	result.Tax = tax
+	result.TotalBeforeTax = totalBeforeTax
+	result.StatusThreshold = decimal.NewFromFloat(sc.rules.Status.Threshold)
+	result.RuleVersion = sc.rules.Version
+	result.Trend = sc.calculateTrend(salaries, finalSalaries)
+	result.FirstPeriod, result.LastPeriod = periodsCovered(salaries)
 
+	used := map[string]bool{sc.rules.Version: true}
+	result.Months = make([]MonthBreakdown, len(salaries))
 	for i, salary := range salaries {
-		adjustedSalary := salary.Salary
-
-		// December: +10% holiday bonus
-		if salary.Month == 12 {
-			adjustedSalary *= 1.10
+		currency := original[i].Currency
+		if currency == "" {
+			currency = sc.currency
 		}
-
-		// Summer months (June, July, August): -5% deduction
-		if salary.Month == 6 || salary.Month == 7 || salary.Month == 8 {
-			adjustedSalary *= 0.95
+		monthRules := sc.rulesFor(salary)
+		used[monthRules.Version] = true
+		result.Months[i] = MonthBreakdown{
+			Year:         salary.Year,
+			Month:        salary.Month,
+			RuleVersion:  monthRules.Version,
+			Original:     original[i].Salary,
+			Currency:     currency,
+			ExchangeRate: exchangeRates[i],
+			Converted:    salary.Salary,
+			Adjustments:  monthRules.AdjustmentNames(salary.Month),
+			Multiplier:   monthRules.Multiplier(salary.Month),
+			Adjusted:     adjustedSalaries[i],
+			Tax:          adjustedSalaries[i].Sub(finalSalaries[i]),
+			Final:        finalSalaries[i],
 		}
+	}
+	for _, version := range sc.history.Versions() {
+		if used[version] {
+			result.RuleVersions = append(result.RuleVersions, version)
+		}
+	}
+
+	return result
+}
+
+func (sc *SalaryCalculator) applySeasonalAdjustments(salaries []models.Salary) []decimal.Decimal {
+	adjusted := make([]decimal.Decimal, len(salaries))
 
-		adjusted[i] = adjustedSalary
+	for i, salary := range salaries {
+		adjusted[i] = salary.Salary.Mul(sc.rulesFor(salary).Multiplier(salary.Month))
 	}
 
 	return adjusted
 }
 
-func (sc *SalaryCalculator) sumSalaries(salaries []float64) float64 {
-	total := 0.0
+func (sc *SalaryCalculator) sumSalaries(salaries []decimal.Decimal) decimal.Decimal {
+	total := decimal.Zero
 	for _, salary := range salaries {
-		total += salary
+		total = total.Add(salary)
 	}
 	return total
 }
 
-func (sc *SalaryCalculator) applyTaxDeduction(salaries []float64, total float64) []float64 {
-	// If total salary > 10,000: apply 7% tax deduction to each salary
-	if total > 10000 {
-		taxedSalaries := make([]float64, len(salaries))
+func (sc *SalaryCalculator) applyTax(salaries []decimal.Decimal, total decimal.Decimal) ([]decimal.Decimal, TaxBreakdown) {
+	taxRule := sc.rules.Tax
+	if !taxRule.IsProgressive() {
+		return sc.applyFlatTax(salaries, total)
+	}
+	if taxRule.IsMonthly() {
+		return sc.applyMonthlyBrackets(salaries)
+	}
+	return sc.applyAggregateBrackets(salaries, total)
+}
+
+func (sc *SalaryCalculator) applyFlatTax(salaries []decimal.Decimal, total decimal.Decimal) ([]decimal.Decimal, TaxBreakdown) {
+	taxRule := sc.rules.Tax
+	breakdown := TaxBreakdown{Mode: rules.TaxModeFlat, TotalTax: decimal.Zero}
+
+	// Once the total exceeds the threshold, deduct the tax rate from each salary
+	if total.GreaterThan(decimal.NewFromFloat(taxRule.Threshold)) {
+		rate := decimal.NewFromFloat(taxRule.Rate)
+		keep := decimal.NewFromInt(1).Sub(rate)
+
+		taxedSalaries := make([]decimal.Decimal, len(salaries))
 		for i, salary := range salaries {
-			taxedSalaries[i] = salary * 0.93 // 7% tax deduction
+			taxedSalaries[i] = salary.Mul(keep)
+		}
+		breakdown.TotalTax = total.Mul(rate)
+		breakdown.Brackets = []rules.BracketTax{
+			{Rate: taxRule.Rate, Taxable: total, Tax: breakdown.TotalTax},
 		}
-		return taxedSalaries
+		return taxedSalaries, breakdown
 	}
 
-	return salaries
+	return salaries, breakdown
 }
 
-func (sc *SalaryCalculator) calculateFinalStats(salaries []float64) *SalaryCalculationResult {
+// applyAggregateBrackets taxes the total and allocates the tax back to each
+// month in proportion to its amount. The last month takes the remainder so
+// the allocations add up to the tax exactly.
+func (sc *SalaryCalculator) applyAggregateBrackets(salaries []decimal.Decimal, total decimal.Decimal) ([]decimal.Decimal, TaxBreakdown) {
+	breakdown := TaxBreakdown{
+		Mode:     rules.TaxModeProgressive,
+		Basis:    rules.TaxBasisAggregate,
+		TotalTax: decimal.Zero,
+		Brackets: sc.rules.Tax.ProgressiveTax(total),
+	}
+	for _, bracket := range breakdown.Brackets {
+		breakdown.TotalTax = breakdown.TotalTax.Add(bracket.Tax)
+	}
+	if !total.IsPositive() || breakdown.TotalTax.IsZero() {
+		return salaries, breakdown
+	}
+
+	taxedSalaries := make([]decimal.Decimal, len(salaries))
+	allocated := decimal.Zero
+	for i, salary := range salaries {
+		share := breakdown.TotalTax.Sub(allocated)
+		if i < len(salaries)-1 {
+			share = salary.Mul(breakdown.TotalTax).Div(total)
+		}
+		allocated = allocated.Add(share)
+		taxedSalaries[i] = salary.Sub(share)
+	}
+	return taxedSalaries, breakdown
+}
+
+// applyMonthlyBrackets taxes each month on its own and sums the per-bracket
+// amounts across months.
+func (sc *SalaryCalculator) applyMonthlyBrackets(salaries []decimal.Decimal) ([]decimal.Decimal, TaxBreakdown) {
+	breakdown := TaxBreakdown{
+		Mode:     rules.TaxModeProgressive,
+		Basis:    rules.TaxBasisMonthly,
+		TotalTax: decimal.Zero,
+	}
+
+	taxedSalaries := make([]decimal.Decimal, len(salaries))
+	for i, salary := range salaries {
+		monthly := sc.rules.Tax.ProgressiveTax(salary)
+		if breakdown.Brackets == nil {
+			breakdown.Brackets = monthly
+		} else {
+			for j := range monthly {
+				breakdown.Brackets[j].Taxable = breakdown.Brackets[j].Taxable.Add(monthly[j].Taxable)
+				breakdown.Brackets[j].Tax = breakdown.Brackets[j].Tax.Add(monthly[j].Tax)
+			}
+		}
+
+		tax := decimal.Zero
+		for _, bracket := range monthly {
+			tax = tax.Add(bracket.Tax)
+		}
+		taxedSalaries[i] = salary.Sub(tax)
+		breakdown.TotalTax = breakdown.TotalTax.Add(tax)
+	}
+
+	return taxedSalaries, breakdown
+}
+
+func (sc *SalaryCalculator) calculateFinalStats(salaries []decimal.Decimal) *SalaryCalculationResult {
 	if len(salaries) == 0 {
 		return &SalaryCalculationResult{
-			AverageSalary: 0,
-			HighestSalary: 0,
-			SumOfSalaries: 0,
+			AverageSalary: decimal.Zero,
+			HighestSalary: decimal.Zero,
+			SumOfSalaries: decimal.Zero,
 			Status:        StatusRed,
 		}
 	}
@@ -103,32 +370,85 @@ func (sc *SalaryCalculator) calculateFinalStats(salaries []float64) *SalaryCalcu
 	sum := sc.sumSalaries(salaries)
 
 	// Calculate average
-	average := sum / float64(len(salaries))
+	average := sum.Div(decimal.NewFromInt(int64(len(salaries))))
 
 	// Find highest
-	highest := salaries[0]
-	for _, salary := range salaries {
-		if salary > highest {
-			highest = salary
-		}
-	}
+	highest := decimal.Max(salaries[0], salaries[1:]...)
+
+	// Describe the distribution
+	sorted := make([]decimal.Decimal, len(salaries))
+	copy(sorted, salaries)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
 
-	// Determine status based on average
-	status := sc.determineStatus(average)
+	// Determine status from the exact sum, since the average may not be
+	// representable
+	status := sc.determineStatus(sum, len(salaries))
 
 	return &SalaryCalculationResult{
-		AverageSalary: average,
-		HighestSalary: highest,
-		SumOfSalaries: sum,
-		Status:        status,
+		AverageSalary:     average.Round(moneyPlaces),
+		HighestSalary:     highest.Round(moneyPlaces),
+		SumOfSalaries:     sum.Round(moneyPlaces),
+		LowestSalary:      sorted[0].Round(moneyPlaces),
+		MedianSalary:      percentile(sorted, decimal.NewFromFloat(0.5)).Round(moneyPlaces),
+		StandardDeviation: standardDeviation(salaries, average).Round(moneyPlaces),
+		Percentile25:      percentile(sorted, decimal.NewFromFloat(0.25)).Round(moneyPlaces),
+		Percentile75:      percentile(sorted, decimal.NewFromFloat(0.75)).Round(moneyPlaces),
+		RecordCount:       len(salaries),
+		Status:            status,
+	}
+}
+
+// percentile interpolates linearly between the closest ranks of sorted
+// amounts. p is a fraction.
+func percentile(sorted []decimal.Decimal, p decimal.Decimal) decimal.Decimal {
+	rank := p.Mul(decimal.NewFromInt(int64(len(sorted) - 1)))
+	lower := int(rank.IntPart())
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	fraction := rank.Sub(decimal.NewFromInt(int64(lower)))
+	return sorted[lower].Add(sorted[lower+1].Sub(sorted[lower]).Mul(fraction))
+}
+
+// standardDeviation is the population standard deviation. The variance is
+// exact; only its square root is taken in floating point.
+func standardDeviation(salaries []decimal.Decimal, average decimal.Decimal) decimal.Decimal {
+	squares := decimal.Zero
+	for _, salary := range salaries {
+		deviation := salary.Sub(average)
+		squares = squares.Add(deviation.Mul(deviation))
+	}
+	variance := squares.Div(decimal.NewFromInt(int64(len(salaries))))
+	return decimal.NewFromFloat(math.Sqrt(variance.InexactFloat64()))
+}
+
+func periodsCovered(salaries []models.Salary) (*models.Period, *models.Period) {
+	if len(salaries) == 0 {
+		return nil, nil
+	}
+	first := models.Period{Year: salaries[0].Year, Month: salaries[0].Month}
+	last := first
+	for _, salary := range salaries[1:] {
+		period := models.Period{Year: salary.Year, Month: salary.Month}
+		if period.Before(first) {
+			first = period
+		}
+		if last.Before(period) {
+			last = period
+		}
 	}
+	return &first, &last
 }
 
-func (sc *SalaryCalculator) determineStatus(averageSalary float64) string {
-	switch {
-	case averageSalary > 2000:
+// determineStatus compares the average of count salaries with the threshold.
+// It compares sum against threshold * count, which is the same without
+// dividing.
+func (sc *SalaryCalculator) determineStatus(sum decimal.Decimal, count int) string {
+	threshold := decimal.NewFromFloat(sc.rules.Status.Threshold).Mul(decimal.NewFromInt(int64(count)))
+	switch sum.Cmp(threshold) {
+	case 1:
 		return StatusGreen
-	case averageSalary == 2000:
+	case 0:
 		return StatusOrange
 	default:
 		return StatusRed
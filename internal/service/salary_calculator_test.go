@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/rules"
+	"github.com/shopspring/decimal"
 )
 
 // ============================================
@@ -21,64 +23,64 @@ func TestSalaryCalculator_ApplySeasonalAdjustments(t *testing.T) {
 		{
 			name: "December bonus adjustment",
 			salaries: []models.Salary{
-				{Month: 12, Salary: 2000},
+				{Month: 12, Salary: decimal.NewFromFloat(2000)},
 			},
 			expected: []float64{2200}, // 2000 * 1.10
 		},
 		{
 			name: "Summer months adjustment - June",
 			salaries: []models.Salary{
-				{Month: 6, Salary: 2000},
+				{Month: 6, Salary: decimal.NewFromFloat(2000)},
 			},
 			expected: []float64{1900}, // 2000 * 0.95
 		},
 		{
 			name: "Summer months adjustment - July",
 			salaries: []models.Salary{
-				{Month: 7, Salary: 2000},
+				{Month: 7, Salary: decimal.NewFromFloat(2000)},
 			},
 			expected: []float64{1900}, // 2000 * 0.95
 		},
 		{
 			name: "Summer months adjustment - August",
 			salaries: []models.Salary{
-				{Month: 8, Salary: 2000},
+				{Month: 8, Salary: decimal.NewFromFloat(2000)},
 			},
 			expected: []float64{1900}, // 2000 * 0.95
 		},
 		{
 			name: "Regular months - no adjustment",
 			salaries: []models.Salary{
-				{Month: 1, Salary: 2000},
-				{Month: 3, Salary: 1500},
-				{Month: 9, Salary: 1800},
+				{Month: 1, Salary: decimal.NewFromFloat(2000)},
+				{Month: 3, Salary: decimal.NewFromFloat(1500)},
+				{Month: 9, Salary: decimal.NewFromFloat(1800)},
 			},
 			expected: []float64{2000, 1500, 1800},
 		},
 		{
 			name: "Mixed seasonal adjustments",
 			salaries: []models.Salary{
-				{Month: 12, Salary: 1800}, // +10%
-				{Month: 6, Salary: 1900},  // -5%
-				{Month: 1, Salary: 2000},  // no adjustment
+				{Month: 12, Salary: decimal.NewFromFloat(1800)}, // +10%
+				{Month: 6, Salary: decimal.NewFromFloat(1900)},  // -5%
+				{Month: 1, Salary: decimal.NewFromFloat(2000)},  // no adjustment
 			},
 			expected: []float64{1980, 1805, 2000}, // 1800*1.10, 1900*0.95, 2000
 		},
 		{
 			name: "Multiple December bonuses",
 			salaries: []models.Salary{
-				{Month: 12, Year: 2023, Salary: 2000},
-				{Month: 12, Year: 2024, Salary: 2000},
-				{Month: 12, Year: 2025, Salary: 2000},
+				{Month: 12, Year: 2023, Salary: decimal.NewFromFloat(2000)},
+				{Month: 12, Year: 2024, Salary: decimal.NewFromFloat(2000)},
+				{Month: 12, Year: 2025, Salary: decimal.NewFromFloat(2000)},
 			},
 			expected: []float64{2200, 2200, 2200}, // All get +10%
 		},
 		{
 			name: "All summer months",
 			salaries: []models.Salary{
-				{Month: 6, Salary: 2500},
-				{Month: 7, Salary: 2500},
-				{Month: 8, Salary: 2500},
+				{Month: 6, Salary: decimal.NewFromFloat(2500)},
+				{Month: 7, Salary: decimal.NewFromFloat(2500)},
+				{Month: 8, Salary: decimal.NewFromFloat(2500)},
 			},
 			expected: []float64{2375, 2375, 2375}, // All get -5%
 		},
@@ -93,8 +95,8 @@ func TestSalaryCalculator_ApplySeasonalAdjustments(t *testing.T) {
 			}
 
 			for i, expected := range tt.expected {
-				if abs(result[i]-expected) > 0.01 { // Allow small floating point differences
-					t.Errorf("Expected salary[%d] = %.2f, got %.2f", i, expected, result[i])
+				if abs(result[i].InexactFloat64()-expected) > 0.01 { // Allow small floating point differences
+					t.Errorf("Expected salary[%d] = %.2f, got %.2f", i, expected, result[i].InexactFloat64())
 				}
 			}
 		})
@@ -154,15 +156,15 @@ func TestSalaryCalculator_ApplyTaxDeduction(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calculator.applyTaxDeduction(tt.salaries, tt.total)
+			result, _ := calculator.applyTax(decimals(tt.salaries...), decimal.NewFromFloat(tt.total))
 
 			if len(result) != len(tt.expected) {
 				t.Fatalf("Expected %d results, got %d", len(tt.expected), len(result))
 			}
 
 			for i, expected := range tt.expected {
-				if abs(result[i]-expected) > 0.01 { // Allow small floating point differences
-					t.Errorf("Expected salary[%d] = %.2f, got %.2f", i, expected, result[i])
+				if abs(result[i].InexactFloat64()-expected) > 0.01 { // Allow small floating point differences
+					t.Errorf("Expected salary[%d] = %.2f, got %.2f", i, expected, result[i].InexactFloat64())
 				}
 			}
 		})
@@ -225,7 +227,7 @@ func TestSalaryCalculator_DetermineStatus(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calculator.determineStatus(tt.averageSalary)
+			result := calculator.determineStatus(decimal.NewFromFloat(tt.averageSalary), 1)
 			if result != tt.expectedStatus {
 				t.Errorf("Expected status %s, got %s for average %.2f", tt.expectedStatus, result, tt.averageSalary)
 			}
@@ -251,11 +253,11 @@ func TestSalaryCalculator_CalculateEmployeeStatus_CompleteFlow(t *testing.T) {
 		{
 			name: "NAT1001 scenario - RED status with summer adjustment",
 			salaries: []models.Salary{
-				{Month: 1, Salary: 1200},
-				{Month: 2, Salary: 1300},
-				{Month: 3, Salary: 1400},
-				{Month: 5, Salary: 1500},
-				{Month: 6, Salary: 1600}, // Summer: 1600 * 0.95 = 1520
+				{Month: 1, Salary: decimal.NewFromFloat(1200)},
+				{Month: 2, Salary: decimal.NewFromFloat(1300)},
+				{Month: 3, Salary: decimal.NewFromFloat(1400)},
+				{Month: 5, Salary: decimal.NewFromFloat(1500)},
+				{Month: 6, Salary: decimal.NewFromFloat(1600)}, // Summer: 1600 * 0.95 = 1520
 			},
 			expectedStatus:  StatusRed,
 			expectedAverage: 1384, // (1200+1300+1400+1500+1520)/5
@@ -265,11 +267,11 @@ func TestSalaryCalculator_CalculateEmployeeStatus_CompleteFlow(t *testing.T) {
 		{
 			name: "Real NAT1002 scenario - RED status (low salaries)",
 			salaries: []models.Salary{
-				{Month: 1, Salary: 900},
-				{Month: 2, Salary: 950},
-				{Month: 3, Salary: 980},
-				{Month: 4, Salary: 1100},
-				{Month: 5, Salary: 1150},
+				{Month: 1, Salary: decimal.NewFromFloat(900)},
+				{Month: 2, Salary: decimal.NewFromFloat(950)},
+				{Month: 3, Salary: decimal.NewFromFloat(980)},
+				{Month: 4, Salary: decimal.NewFromFloat(1100)},
+				{Month: 5, Salary: decimal.NewFromFloat(1150)},
 			},
 			expectedStatus:  StatusRed,
 			expectedAverage: 1016,
@@ -279,9 +281,9 @@ func TestSalaryCalculator_CalculateEmployeeStatus_CompleteFlow(t *testing.T) {
 		{
 			name: "ORANGE status scenario - exactly 2000 average",
 			salaries: []models.Salary{
-				{Month: 1, Salary: 1900},
-				{Month: 2, Salary: 2000},
-				{Month: 3, Salary: 2100},
+				{Month: 1, Salary: decimal.NewFromFloat(1900)},
+				{Month: 2, Salary: decimal.NewFromFloat(2000)},
+				{Month: 3, Salary: decimal.NewFromFloat(2100)},
 			},
 			expectedStatus:  StatusOrange,
 			expectedAverage: 2000,
@@ -291,11 +293,11 @@ func TestSalaryCalculator_CalculateEmployeeStatus_CompleteFlow(t *testing.T) {
 		{
 			name: "NAT1004 scenario - RED status (corrected expectation)",
 			salaries: []models.Salary{
-				{Month: 1, Salary: 2000},
-				{Month: 2, Salary: 2050},
-				{Month: 3, Salary: 2100},
-				{Month: 4, Salary: 2200},
-				{Month: 5, Salary: 2300},
+				{Month: 1, Salary: decimal.NewFromFloat(2000)},
+				{Month: 2, Salary: decimal.NewFromFloat(2050)},
+				{Month: 3, Salary: decimal.NewFromFloat(2100)},
+				{Month: 4, Salary: decimal.NewFromFloat(2200)},
+				{Month: 5, Salary: decimal.NewFromFloat(2300)},
 			},
 			// Total: 10650 > 10000, tax applies
 			// After 7% tax: 1860, 1906.5, 1953, 2046, 2139
@@ -307,10 +309,10 @@ func TestSalaryCalculator_CalculateEmployeeStatus_CompleteFlow(t *testing.T) {
 		{
 			name: "HIGH salary with tax deduction",
 			salaries: []models.Salary{
-				{Month: 1, Salary: 3000},
-				{Month: 2, Salary: 3100},
-				{Month: 3, Salary: 3200},
-				{Month: 4, Salary: 3300},
+				{Month: 1, Salary: decimal.NewFromFloat(3000)},
+				{Month: 2, Salary: decimal.NewFromFloat(3100)},
+				{Month: 3, Salary: decimal.NewFromFloat(3200)},
+				{Month: 4, Salary: decimal.NewFromFloat(3300)},
 			},
 			// Total: 12600 > 10000, tax applies
 			expectedStatus:  StatusGreen,
@@ -321,9 +323,9 @@ func TestSalaryCalculator_CalculateEmployeeStatus_CompleteFlow(t *testing.T) {
 		{
 			name: "December bonus scenario",
 			salaries: []models.Salary{
-				{Month: 12, Salary: 2000}, // 2000 * 1.10 = 2200
-				{Month: 1, Salary: 1800},
-				{Month: 2, Salary: 1900},
+				{Month: 12, Salary: decimal.NewFromFloat(2000)}, // 2000 * 1.10 = 2200
+				{Month: 1, Salary: decimal.NewFromFloat(1800)},
+				{Month: 2, Salary: decimal.NewFromFloat(1900)},
 			},
 			expectedStatus:  StatusRed,
 			expectedAverage: 1966.67, // (2200+1800+1900)/3
@@ -333,9 +335,9 @@ func TestSalaryCalculator_CalculateEmployeeStatus_CompleteFlow(t *testing.T) {
 		{
 			name: "All summer months scenario",
 			salaries: []models.Salary{
-				{Month: 6, Salary: 2000}, // 2000 * 0.95 = 1900
-				{Month: 7, Salary: 2100}, // 2100 * 0.95 = 1995
-				{Month: 8, Salary: 2200}, // 2200 * 0.95 = 2090
+				{Month: 6, Salary: decimal.NewFromFloat(2000)}, // 2000 * 0.95 = 1900
+				{Month: 7, Salary: decimal.NewFromFloat(2100)}, // 2100 * 0.95 = 1995
+				{Month: 8, Salary: decimal.NewFromFloat(2200)}, // 2200 * 0.95 = 2090
 			},
 			expectedStatus:  StatusRed,
 			expectedAverage: 1995, // (1900+1995+2090)/3
@@ -345,9 +347,9 @@ func TestSalaryCalculator_CalculateEmployeeStatus_CompleteFlow(t *testing.T) {
 		{
 			name: "Border case - exactly 2000 average",
 			salaries: []models.Salary{
-				{Month: 1, Salary: 1950},
-				{Month: 2, Salary: 2000},
-				{Month: 3, Salary: 2050},
+				{Month: 1, Salary: decimal.NewFromFloat(1950)},
+				{Month: 2, Salary: decimal.NewFromFloat(2000)},
+				{Month: 3, Salary: decimal.NewFromFloat(2050)},
 			},
 			expectedStatus:  StatusOrange,
 			expectedAverage: 2000,
@@ -357,9 +359,9 @@ func TestSalaryCalculator_CalculateEmployeeStatus_CompleteFlow(t *testing.T) {
 		{
 			name: "December and summer months together",
 			salaries: []models.Salary{
-				{Month: 6, Salary: 2000},  // -5% = 1900
-				{Month: 12, Salary: 2000}, // +10% = 2200
-				{Month: 1, Salary: 2000},  // No change
+				{Month: 6, Salary: decimal.NewFromFloat(2000)},  // -5% = 1900
+				{Month: 12, Salary: decimal.NewFromFloat(2000)}, // +10% = 2200
+				{Month: 1, Salary: decimal.NewFromFloat(2000)},  // No change
 			},
 			expectedStatus:  StatusGreen, // Average 2033.33 > 2000
 			expectedAverage: 2033.33,     // (1900+2200+2000)/3
@@ -376,16 +378,16 @@ func TestSalaryCalculator_CalculateEmployeeStatus_CompleteFlow(t *testing.T) {
 				t.Errorf("Expected status %s, got %s", tt.expectedStatus, result.Status)
 			}
 
-			if abs(result.AverageSalary-tt.expectedAverage) > 0.5 {
-				t.Errorf("Expected average %.2f, got %.2f", tt.expectedAverage, result.AverageSalary)
+			if abs(result.AverageSalary.InexactFloat64()-tt.expectedAverage) > 0.5 {
+				t.Errorf("Expected average %.2f, got %.2f", tt.expectedAverage, result.AverageSalary.InexactFloat64())
 			}
 
-			if abs(result.HighestSalary-tt.expectedHighest) > 0.5 {
-				t.Errorf("Expected highest %.2f, got %.2f", tt.expectedHighest, result.HighestSalary)
+			if abs(result.HighestSalary.InexactFloat64()-tt.expectedHighest) > 0.5 {
+				t.Errorf("Expected highest %.2f, got %.2f", tt.expectedHighest, result.HighestSalary.InexactFloat64())
 			}
 
-			if abs(result.SumOfSalaries-tt.expectedSum) > 0.5 {
-				t.Errorf("Expected sum %.2f, got %.2f", tt.expectedSum, result.SumOfSalaries)
+			if abs(result.SumOfSalaries.InexactFloat64()-tt.expectedSum) > 0.5 {
+				t.Errorf("Expected sum %.2f, got %.2f", tt.expectedSum, result.SumOfSalaries.InexactFloat64())
 			}
 		})
 	}
@@ -400,16 +402,16 @@ func TestSalaryCalculator_CriticalEdgeCases(t *testing.T) {
 
 	t.Run("Tax threshold exactly 10000 - no tax applied", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 1, Salary: 2500},
-			{Month: 2, Salary: 2500},
-			{Month: 3, Salary: 2500},
-			{Month: 4, Salary: 2500},
+			{Month: 1, Salary: decimal.NewFromFloat(2500)},
+			{Month: 2, Salary: decimal.NewFromFloat(2500)},
+			{Month: 3, Salary: decimal.NewFromFloat(2500)},
+			{Month: 4, Salary: decimal.NewFromFloat(2500)},
 		}
 		result := calculator.CalculateEmployeeStatus(salaries)
 
 		// Total is exactly 10000, should NOT apply tax (requirement: > 10000)
-		if abs(result.SumOfSalaries-10000) > 0.01 {
-			t.Errorf("Expected sum 10000 (no tax), got %.2f", result.SumOfSalaries)
+		if abs(result.SumOfSalaries.InexactFloat64()-10000) > 0.01 {
+			t.Errorf("Expected sum 10000 (no tax), got %.2f", result.SumOfSalaries.InexactFloat64())
 		}
 		if result.Status != StatusGreen {
 			t.Errorf("Expected GREEN status, got %s", result.Status)
@@ -418,42 +420,42 @@ func TestSalaryCalculator_CriticalEdgeCases(t *testing.T) {
 
 	t.Run("Tax threshold 10000.01 - tax applied", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 1, Salary: 2500},
-			{Month: 2, Salary: 2500},
-			{Month: 3, Salary: 2500},
-			{Month: 4, Salary: 2500.01},
+			{Month: 1, Salary: decimal.NewFromFloat(2500)},
+			{Month: 2, Salary: decimal.NewFromFloat(2500)},
+			{Month: 3, Salary: decimal.NewFromFloat(2500)},
+			{Month: 4, Salary: decimal.NewFromFloat(2500.01)},
 		}
 		result := calculator.CalculateEmployeeStatus(salaries)
 
 		// Total is 10000.01 > 10000, should apply 7% tax
 		expectedSum := 10000.01 * 0.93 // 9300.0093
-		if abs(result.SumOfSalaries-expectedSum) > 0.01 {
-			t.Errorf("Expected sum %.2f (with tax), got %.2f", expectedSum, result.SumOfSalaries)
+		if abs(result.SumOfSalaries.InexactFloat64()-expectedSum) > 0.01 {
+			t.Errorf("Expected sum %.2f (with tax), got %.2f", expectedSum, result.SumOfSalaries.InexactFloat64())
 		}
 	})
 
 	t.Run("Status boundary - exactly 2000 after all adjustments", func(t *testing.T) {
 		// This tests floating point precision at the ORANGE boundary
 		salaries := []models.Salary{
-			{Month: 1, Salary: 2000.00},
-			{Month: 2, Salary: 2000.00},
-			{Month: 3, Salary: 2000.00},
+			{Month: 1, Salary: decimal.NewFromFloat(2000.00)},
+			{Month: 2, Salary: decimal.NewFromFloat(2000.00)},
+			{Month: 3, Salary: decimal.NewFromFloat(2000.00)},
 		}
 		result := calculator.CalculateEmployeeStatus(salaries)
 
 		if result.Status != StatusOrange {
 			t.Errorf("Expected ORANGE for exactly 2000 average, got %s", result.Status)
 		}
-		if abs(result.AverageSalary-2000) > 0.01 {
-			t.Errorf("Expected average 2000.00, got %.2f", result.AverageSalary)
+		if abs(result.AverageSalary.InexactFloat64()-2000) > 0.01 {
+			t.Errorf("Expected average 2000.00, got %.2f", result.AverageSalary.InexactFloat64())
 		}
 	})
 
 	t.Run("Just below 2000 average - RED", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 1, Salary: 1999.99},
-			{Month: 2, Salary: 2000.00},
-			{Month: 3, Salary: 2000.00},
+			{Month: 1, Salary: decimal.NewFromFloat(1999.99)},
+			{Month: 2, Salary: decimal.NewFromFloat(2000.00)},
+			{Month: 3, Salary: decimal.NewFromFloat(2000.00)},
 		}
 		result := calculator.CalculateEmployeeStatus(salaries)
 
@@ -464,9 +466,9 @@ func TestSalaryCalculator_CriticalEdgeCases(t *testing.T) {
 
 	t.Run("Just above 2000 average - GREEN", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 1, Salary: 2000.01},
-			{Month: 2, Salary: 2000.00},
-			{Month: 3, Salary: 2000.00},
+			{Month: 1, Salary: decimal.NewFromFloat(2000.01)},
+			{Month: 2, Salary: decimal.NewFromFloat(2000.00)},
+			{Month: 3, Salary: decimal.NewFromFloat(2000.00)},
 		}
 		result := calculator.CalculateEmployeeStatus(salaries)
 
@@ -478,16 +480,16 @@ func TestSalaryCalculator_CriticalEdgeCases(t *testing.T) {
 	t.Run("Summer adjustment keeps total below 10000 - no tax", func(t *testing.T) {
 		// Summer deduction keeps total below 10000
 		salaries := []models.Salary{
-			{Month: 6, Salary: 3400}, // Summer: 3400 * 0.95 = 3230
-			{Month: 7, Salary: 3400}, // Summer: 3400 * 0.95 = 3230
-			{Month: 8, Salary: 3400}, // Summer: 3400 * 0.95 = 3230
+			{Month: 6, Salary: decimal.NewFromFloat(3400)}, // Summer: 3400 * 0.95 = 3230
+			{Month: 7, Salary: decimal.NewFromFloat(3400)}, // Summer: 3400 * 0.95 = 3230
+			{Month: 8, Salary: decimal.NewFromFloat(3400)}, // Summer: 3400 * 0.95 = 3230
 		}
 		// Total after seasonal: 9690 < 10000, no tax
 		result := calculator.CalculateEmployeeStatus(salaries)
 
 		expectedSum := 9690.0
-		if abs(result.SumOfSalaries-expectedSum) > 0.01 {
-			t.Errorf("Expected sum %.2f (no tax due to seasonal), got %.2f", expectedSum, result.SumOfSalaries)
+		if abs(result.SumOfSalaries.InexactFloat64()-expectedSum) > 0.01 {
+			t.Errorf("Expected sum %.2f (no tax due to seasonal), got %.2f", expectedSum, result.SumOfSalaries.InexactFloat64())
 		}
 		if result.Status != StatusGreen {
 			t.Errorf("Expected GREEN status, got %s", result.Status)
@@ -496,26 +498,26 @@ func TestSalaryCalculator_CriticalEdgeCases(t *testing.T) {
 
 	t.Run("December bonus pushes total over 10000 - tax applied", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 12, Salary: 3400}, // +10% = 3740
-			{Month: 1, Salary: 3200},
-			{Month: 2, Salary: 3200},
+			{Month: 12, Salary: decimal.NewFromFloat(3400)}, // +10% = 3740
+			{Month: 1, Salary: decimal.NewFromFloat(3200)},
+			{Month: 2, Salary: decimal.NewFromFloat(3200)},
 		}
 		// Total after seasonal: 10140 > 10000, tax applies
 		// After tax: 3478.2, 2976, 2976 = 9430.2
 		result := calculator.CalculateEmployeeStatus(salaries)
 
 		expectedSum := 9430.2
-		if abs(result.SumOfSalaries-expectedSum) > 0.5 {
-			t.Errorf("Expected sum %.2f (with tax after seasonal), got %.2f", expectedSum, result.SumOfSalaries)
+		if abs(result.SumOfSalaries.InexactFloat64()-expectedSum) > 0.5 {
+			t.Errorf("Expected sum %.2f (with tax after seasonal), got %.2f", expectedSum, result.SumOfSalaries.InexactFloat64())
 		}
 	})
 
 	t.Run("Mixed adjustments with high tax impact", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 12, Salary: 3000}, // +10% = 3300
-			{Month: 6, Salary: 3000},  // -5% = 2850
-			{Month: 1, Salary: 3000},  // No change = 3000
-			{Month: 2, Salary: 3000},  // No change = 3000
+			{Month: 12, Salary: decimal.NewFromFloat(3000)}, // +10% = 3300
+			{Month: 6, Salary: decimal.NewFromFloat(3000)},  // -5% = 2850
+			{Month: 1, Salary: decimal.NewFromFloat(3000)},  // No change = 3000
+			{Month: 2, Salary: decimal.NewFromFloat(3000)},  // No change = 3000
 		}
 		// Total before tax: 3300 + 2850 + 3000 + 3000 = 12150 > 10000
 		// After tax: 3069, 2650.5, 2790, 2790 = 11299.5
@@ -527,8 +529,8 @@ func TestSalaryCalculator_CriticalEdgeCases(t *testing.T) {
 
 		// Expected average: (3069 + 2650.5 + 2790 + 2790) / 4 = 2824.875
 		expectedAverage := 2824.875
-		if abs(result.AverageSalary-expectedAverage) > 1.0 {
-			t.Errorf("Expected average %.3f, got %.3f", expectedAverage, result.AverageSalary)
+		if abs(result.AverageSalary.InexactFloat64()-expectedAverage) > 1.0 {
+			t.Errorf("Expected average %.3f, got %.3f", expectedAverage, result.AverageSalary.InexactFloat64())
 		}
 	})
 }
@@ -542,23 +544,23 @@ func TestSalaryCalculator_AdditionalEdgeCases(t *testing.T) {
 
 	t.Run("Single salary record", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 1, Salary: 2500},
+			{Month: 1, Salary: decimal.NewFromFloat(2500)},
 		}
 		result := calculator.CalculateEmployeeStatus(salaries)
 
 		if result.Status != StatusGreen {
 			t.Errorf("Expected GREEN status for single high salary, got %s", result.Status)
 		}
-		if abs(result.AverageSalary-2500) > 0.01 {
-			t.Errorf("Expected average 2500, got %.2f", result.AverageSalary)
+		if abs(result.AverageSalary.InexactFloat64()-2500) > 0.01 {
+			t.Errorf("Expected average 2500, got %.2f", result.AverageSalary.InexactFloat64())
 		}
 	})
 
 	t.Run("All summer months with tax", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 6, Salary: 3600}, // 3420
-			{Month: 7, Salary: 3600}, // 3420
-			{Month: 8, Salary: 3600}, // 3420
+			{Month: 6, Salary: decimal.NewFromFloat(3600)}, // 3420
+			{Month: 7, Salary: decimal.NewFromFloat(3600)}, // 3420
+			{Month: 8, Salary: decimal.NewFromFloat(3600)}, // 3420
 		}
 		// Total: 10260 > 10000, tax applies
 		// After tax: 3180.6, 3180.6, 3180.6
@@ -576,32 +578,32 @@ func TestSalaryCalculator_AdditionalEdgeCases(t *testing.T) {
 		if result.Status != StatusRed {
 			t.Errorf("Expected RED status for empty salaries, got %s", result.Status)
 		}
-		if result.AverageSalary != 0 {
-			t.Errorf("Expected average 0, got %.2f", result.AverageSalary)
+		if result.AverageSalary.InexactFloat64() != 0 {
+			t.Errorf("Expected average 0, got %.2f", result.AverageSalary.InexactFloat64())
 		}
 	})
 
 	t.Run("Zero salary values", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 1, Salary: 0},
-			{Month: 2, Salary: 0},
-			{Month: 3, Salary: 0},
+			{Month: 1, Salary: decimal.NewFromFloat(0)},
+			{Month: 2, Salary: decimal.NewFromFloat(0)},
+			{Month: 3, Salary: decimal.NewFromFloat(0)},
 		}
 		result := calculator.CalculateEmployeeStatus(salaries)
 
 		if result.Status != StatusRed {
 			t.Errorf("Expected RED status for zero salaries, got %s", result.Status)
 		}
-		if result.AverageSalary != 0 {
-			t.Errorf("Expected average 0, got %.2f", result.AverageSalary)
+		if result.AverageSalary.InexactFloat64() != 0 {
+			t.Errorf("Expected average 0, got %.2f", result.AverageSalary.InexactFloat64())
 		}
 	})
 
 	t.Run("Very large salary values", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 1, Salary: 50000},
-			{Month: 2, Salary: 51000},
-			{Month: 3, Salary: 52000},
+			{Month: 1, Salary: decimal.NewFromFloat(50000)},
+			{Month: 2, Salary: decimal.NewFromFloat(51000)},
+			{Month: 3, Salary: decimal.NewFromFloat(52000)},
 		}
 		// Total: 153000 > 10000, tax applies
 		// After tax: 46500, 47430, 48360 = 142290
@@ -614,9 +616,9 @@ func TestSalaryCalculator_AdditionalEdgeCases(t *testing.T) {
 
 	t.Run("All December months (multiple years)", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 12, Year: 2023, Salary: 2000}, // +10%
-			{Month: 12, Year: 2024, Salary: 2000}, // +10%
-			{Month: 12, Year: 2025, Salary: 2000}, // +10%
+			{Month: 12, Year: 2023, Salary: decimal.NewFromFloat(2000)}, // +10%
+			{Month: 12, Year: 2024, Salary: decimal.NewFromFloat(2000)}, // +10%
+			{Month: 12, Year: 2025, Salary: decimal.NewFromFloat(2000)}, // +10%
 		}
 		// All get +10%: 2200, 2200, 2200 = 6600
 		result := calculator.CalculateEmployeeStatus(salaries)
@@ -625,17 +627,17 @@ func TestSalaryCalculator_AdditionalEdgeCases(t *testing.T) {
 			t.Errorf("Expected GREEN status, got %s", result.Status)
 		}
 		expectedAverage := 2200.0
-		if abs(result.AverageSalary-expectedAverage) > 0.01 {
-			t.Errorf("Expected average %.2f, got %.2f", expectedAverage, result.AverageSalary)
+		if abs(result.AverageSalary.InexactFloat64()-expectedAverage) > 0.01 {
+			t.Errorf("Expected average %.2f, got %.2f", expectedAverage, result.AverageSalary.InexactFloat64())
 		}
 	})
 
 	t.Run("December and all summer months", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 6, Salary: 2000},  // -5% = 1900
-			{Month: 7, Salary: 2000},  // -5% = 1900
-			{Month: 8, Salary: 2000},  // -5% = 1900
-			{Month: 12, Salary: 2000}, // +10% = 2200
+			{Month: 6, Salary: decimal.NewFromFloat(2000)},  // -5% = 1900
+			{Month: 7, Salary: decimal.NewFromFloat(2000)},  // -5% = 1900
+			{Month: 8, Salary: decimal.NewFromFloat(2000)},  // -5% = 1900
+			{Month: 12, Salary: decimal.NewFromFloat(2000)}, // +10% = 2200
 		}
 		// Total: 7900 < 10000, no tax
 		// Average: 1975
@@ -645,16 +647,16 @@ func TestSalaryCalculator_AdditionalEdgeCases(t *testing.T) {
 			t.Errorf("Expected RED status, got %s", result.Status)
 		}
 		expectedAverage := 1975.0
-		if abs(result.AverageSalary-expectedAverage) > 0.01 {
-			t.Errorf("Expected average %.2f, got %.2f", expectedAverage, result.AverageSalary)
+		if abs(result.AverageSalary.InexactFloat64()-expectedAverage) > 0.01 {
+			t.Errorf("Expected average %.2f, got %.2f", expectedAverage, result.AverageSalary.InexactFloat64())
 		}
 	})
 
 	t.Run("Salary with decimal precision", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 1, Salary: 1999.99},
-			{Month: 2, Salary: 2000.00},
-			{Month: 3, Salary: 2000.01},
+			{Month: 1, Salary: decimal.NewFromFloat(1999.99)},
+			{Month: 2, Salary: decimal.NewFromFloat(2000.00)},
+			{Month: 3, Salary: decimal.NewFromFloat(2000.01)},
 		}
 		result := calculator.CalculateEmployeeStatus(salaries)
 
@@ -666,12 +668,12 @@ func TestSalaryCalculator_AdditionalEdgeCases(t *testing.T) {
 
 	t.Run("Complex scenario - NAT1008 high earner", func(t *testing.T) {
 		salaries := []models.Salary{
-			{Month: 10, Salary: 2200},
-			{Month: 11, Salary: 2300},
-			{Month: 12, Salary: 2400}, // +10% = 2640
-			{Month: 1, Salary: 2500},
-			{Month: 2, Salary: 2600},
-			{Month: 3, Salary: 2800},
+			{Month: 10, Salary: decimal.NewFromFloat(2200)},
+			{Month: 11, Salary: decimal.NewFromFloat(2300)},
+			{Month: 12, Salary: decimal.NewFromFloat(2400)}, // +10% = 2640
+			{Month: 1, Salary: decimal.NewFromFloat(2500)},
+			{Month: 2, Salary: decimal.NewFromFloat(2600)},
+			{Month: 3, Salary: decimal.NewFromFloat(2800)},
 		}
 		// Total before tax: 2200 + 2300 + 2640 + 2500 + 2600 + 2800 = 15040 > 10000
 		// After 7% tax all values * 0.93
@@ -684,8 +686,219 @@ func TestSalaryCalculator_AdditionalEdgeCases(t *testing.T) {
 
 		// Average: 13987.2 / 6 = 2331.2
 		expectedAverage := 2331.2
-		if abs(result.AverageSalary-expectedAverage) > 1.0 {
-			t.Errorf("Expected average %.2f, got %.2f", expectedAverage, result.AverageSalary)
+		if abs(result.AverageSalary.InexactFloat64()-expectedAverage) > 1.0 {
+			t.Errorf("Expected average %.2f, got %.2f", expectedAverage, result.AverageSalary.InexactFloat64())
+		}
+	})
+}
+
+// ============================================
+// Decimal Precision Tests
+// ============================================
+
+func TestSalaryCalculator_ExactDecimalArithmetic(t *testing.T) {
+	calculator := NewSalaryCalculator()
+
+	t.Run("Seasonal multipliers land exactly on the ORANGE threshold", func(t *testing.T) {
+		salaries := []models.Salary{
+			{Month: 12, Salary: decimal.RequireFromString("2000.00")}, // 2200.000
+			{Month: 6, Salary: decimal.RequireFromString("2000.00")},  // 1900.000
+			{Month: 1, Salary: decimal.RequireFromString("1900.00")},
+		}
+		result := calculator.CalculateEmployeeStatus(salaries)
+
+		if result.Status != StatusOrange {
+			t.Errorf("Expected ORANGE status, got %s", result.Status)
+		}
+		if !result.SumOfSalaries.Equal(decimal.NewFromInt(6000)) {
+			t.Errorf("Expected sum exactly 6000, got %s", result.SumOfSalaries)
+		}
+	})
+
+	t.Run("Reported amounts are rounded to cents", func(t *testing.T) {
+		salaries := []models.Salary{
+			{Month: 1, Salary: decimal.RequireFromString("1000.00")},
+			{Month: 2, Salary: decimal.RequireFromString("1000.00")},
+			{Month: 3, Salary: decimal.RequireFromString("1000.01")},
+		}
+		result := calculator.CalculateEmployeeStatus(salaries)
+
+		// 3000.01 / 3 = 1000.00333...
+		if result.AverageSalary.String() != "1000" {
+			t.Errorf("Expected average 1000, got %s", result.AverageSalary)
+		}
+	})
+
+	t.Run("Rounding is half away from zero", func(t *testing.T) {
+		salaries := []models.Salary{
+			{Month: 6, Salary: decimal.RequireFromString("1000.10")}, // 950.095
+		}
+		result := calculator.CalculateEmployeeStatus(salaries)
+
+		if result.SumOfSalaries.String() != "950.1" {
+			t.Errorf("Expected sum 950.10, got %s", result.SumOfSalaries)
+		}
+	})
+}
+
+// ============================================
+// Configured Rules Tests
+// ============================================
+
+func TestSalaryCalculator_WithRules(t *testing.T) {
+	calculator := NewSalaryCalculatorWithRules(&rules.RuleSet{
+		Version: "test",
+		Adjustments: []rules.SeasonalAdjustment{
+			{Name: "Year-end bonus", Months: []int{11, 12}, Multiplier: 1.20},
+		},
+		Tax:    rules.TaxRule{Threshold: 5000, Rate: 0.10},
+		Status: rules.StatusRule{Threshold: 1800},
+	})
+
+	salaries := []models.Salary{
+		{Month: 11, Salary: decimal.NewFromFloat(2000)}, // +20% = 2400
+		{Month: 6, Salary: decimal.NewFromFloat(2000)},  // no summer rule configured
+		{Month: 1, Salary: decimal.NewFromFloat(1000)},
+	}
+	// Total before tax: 5400 > 5000, 10% tax applies
+	// After tax: 2160, 1800, 900 = 4860
+	result := calculator.CalculateEmployeeStatus(salaries)
+
+	if abs(result.SumOfSalaries.InexactFloat64()-4860) > 0.01 {
+		t.Errorf("Expected sum 4860.00, got %.2f", result.SumOfSalaries.InexactFloat64())
+	}
+	if result.Status != StatusRed { // Average 1620 < 1800
+		t.Errorf("Expected RED status, got %s", result.Status)
+	}
+	if calculator.RuleVersion() != "test" {
+		t.Errorf("Expected rule version test, got %s", calculator.RuleVersion())
+	}
+}
+
+func TestSalaryCalculator_RuleHistory(t *testing.T) {
+	original := rules.Default()
+	revised := rules.Default()
+	revised.Version = "2025"
+	revised.EffectiveFrom = "2025-01"
+	revised.Adjustments = []rules.SeasonalAdjustment{
+		{Name: "December holiday bonus", Months: []int{12}, Multiplier: 1.20},
+	}
+	revised.Status.Threshold = 2100
+
+	history, err := rules.NewHistory(original, revised)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	calculator := NewSalaryCalculatorWithHistory(history)
+
+	salaries := []models.Salary{
+		{Year: 2024, Month: 12, Salary: decimal.NewFromFloat(2000)}, // 2024 bonus +10% = 2200
+		{Year: 2025, Month: 1, Salary: decimal.NewFromFloat(2000)},
+		{Year: 2025, Month: 12, Salary: decimal.NewFromFloat(2000)}, // 2025 bonus +20% = 2400
+	}
+	// Total 6600, average 2200 against the 2025 threshold of 2100
+	result := calculator.CalculateEmployeeStatus(salaries)
+
+	if abs(result.SumOfSalaries.InexactFloat64()-6600) > 0.01 {
+		t.Errorf("Expected sum 6600.00, got %.2f", result.SumOfSalaries.InexactFloat64())
+	}
+	if result.Status != StatusGreen {
+		t.Errorf("Expected GREEN status, got %s", result.Status)
+	}
+	if result.RuleVersion != "2025" {
+		t.Errorf("Expected series rule version 2025, got %s", result.RuleVersion)
+	}
+	if len(result.RuleVersions) != 2 || result.RuleVersions[0] != "default" || result.RuleVersions[1] != "2025" {
+		t.Errorf("Expected rule versions [default 2025], got %v", result.RuleVersions)
+	}
+	if result.Months[0].RuleVersion != "default" || result.Months[2].RuleVersion != "2025" {
+		t.Errorf("Expected month rule versions default and 2025, got %s and %s", result.Months[0].RuleVersion, result.Months[2].RuleVersion)
+	}
+
+	// A series entirely before the revision is unaffected by it
+	result = calculator.CalculateEmployeeStatus(salaries[:1])
+	if result.StatusThreshold.InexactFloat64() != 2000 || len(result.RuleVersions) != 1 {
+		t.Errorf("Expected only the default rules, got threshold %s and versions %v", result.StatusThreshold, result.RuleVersions)
+	}
+}
+
+func TestSalaryCalculator_ProgressiveTax(t *testing.T) {
+	progressive := func(basis string) *SalaryCalculator {
+		ruleSet := rules.Default()
+		ruleSet.Tax = rules.TaxRule{
+			Mode:  rules.TaxModeProgressive,
+			Basis: basis,
+			Brackets: []rules.TaxBracket{
+				{From: 0, Rate: 0},
+				{From: 10000, Rate: 0.07},
+				{From: 20000, Rate: 0.15},
+			},
+		}
+		return NewSalaryCalculatorWithRules(ruleSet)
+	}
+
+	t.Run("Aggregate has no cliff at the threshold", func(t *testing.T) {
+		salaries := []models.Salary{
+			{Month: 1, Salary: decimal.NewFromFloat(2500)},
+			{Month: 2, Salary: decimal.NewFromFloat(2500)},
+			{Month: 3, Salary: decimal.NewFromFloat(2500)},
+			{Month: 4, Salary: decimal.NewFromFloat(2500.01)},
+		}
+		result := progressive(rules.TaxBasisAggregate).CalculateEmployeeStatus(salaries)
+
+		// Only the 0.01 above 10000 is taxed
+		if abs(result.SumOfSalaries.InexactFloat64()-10000.0093) > 0.001 {
+			t.Errorf("Expected sum 10000.0093, got %.4f", result.SumOfSalaries.InexactFloat64())
+		}
+		if abs(result.Tax.TotalTax.InexactFloat64()-0.0007) > 0.0001 {
+			t.Errorf("Expected total tax 0.0007, got %.4f", result.Tax.TotalTax.InexactFloat64())
+		}
+	})
+
+	t.Run("Aggregate tax is allocated proportionally", func(t *testing.T) {
+		salaries := []models.Salary{
+			{Month: 1, Salary: decimal.NewFromFloat(8000)},
+			{Month: 2, Salary: decimal.NewFromFloat(8000)},
+			{Month: 3, Salary: decimal.NewFromFloat(9000)},
+		}
+		// Total 25000: 10000 * 7% + 5000 * 15% = 1450 tax, effective rate 5.8%
+		calculator := progressive(rules.TaxBasisAggregate)
+		result := calculator.CalculateEmployeeStatus(salaries)
+
+		if abs(result.Tax.TotalTax.InexactFloat64()-1450) > 0.01 {
+			t.Errorf("Expected total tax 1450, got %.2f", result.Tax.TotalTax.InexactFloat64())
+		}
+		if abs(result.SumOfSalaries.InexactFloat64()-23550) > 0.01 {
+			t.Errorf("Expected sum 23550, got %.2f", result.SumOfSalaries.InexactFloat64())
+		}
+		if abs(result.HighestSalary.InexactFloat64()-9000*(1-0.058)) > 0.01 {
+			t.Errorf("Expected highest %.2f, got %.2f", 9000*(1-0.058), result.HighestSalary.InexactFloat64())
+		}
+
+		expectedBrackets := []float64{0, 700, 750}
+		for i, expected := range expectedBrackets {
+			if abs(result.Tax.Brackets[i].Tax.InexactFloat64()-expected) > 0.01 {
+				t.Errorf("Expected bracket %d tax %.2f, got %.2f", i, expected, result.Tax.Brackets[i].Tax.InexactFloat64())
+			}
+		}
+	})
+
+	t.Run("Monthly basis taxes each month separately", func(t *testing.T) {
+		salaries := []models.Salary{
+			{Month: 1, Salary: decimal.NewFromFloat(12000)}, // 2000 * 7% = 140
+			{Month: 2, Salary: decimal.NewFromFloat(9000)},  // no tax
+			{Month: 3, Salary: decimal.NewFromFloat(21000)}, // 10000 * 7% + 1000 * 15% = 850
+		}
+		result := progressive(rules.TaxBasisMonthly).CalculateEmployeeStatus(salaries)
+
+		if abs(result.Tax.TotalTax.InexactFloat64()-990) > 0.01 {
+			t.Errorf("Expected total tax 990, got %.2f", result.Tax.TotalTax.InexactFloat64())
+		}
+		if abs(result.HighestSalary.InexactFloat64()-20150) > 0.01 {
+			t.Errorf("Expected highest 20150, got %.2f", result.HighestSalary.InexactFloat64())
+		}
+		if abs(result.Tax.Brackets[1].Taxable.InexactFloat64()-12000) > 0.01 {
+			t.Errorf("Expected 12000 taxable in the 7%% bracket, got %.2f", result.Tax.Brackets[1].Taxable.InexactFloat64())
 		}
 	})
 }
@@ -694,6 +907,15 @@ func TestSalaryCalculator_AdditionalEdgeCases(t *testing.T) {
 // Helper Functions
 // ============================================
 
+// decimals converts float literals to decimal amounts.
+func decimals(values ...float64) []decimal.Decimal {
+	result := make([]decimal.Decimal, len(values))
+	for i, value := range values {
+		result[i] = decimal.NewFromFloat(value)
+	}
+	return result
+}
+
 // Helper function for floating point comparison.
 func abs(x float64) float64 {
 	if x < 0 {
@@ -701,3 +923,129 @@ func abs(x float64) float64 {
 	}
 	return x
 }
+
+// ============================================
+// Trend Tests
+// ============================================
+
+func TestSalaryCalculator_Trend(t *testing.T) {
+	series := func(amounts ...float64) []models.Salary {
+		salaries := make([]models.Salary, len(amounts))
+		for i, amount := range amounts {
+			// March to May avoids seasonal adjustments
+			salaries[i] = models.Salary{Year: 2025, Month: 3 + i, Salary: decimal.NewFromFloat(amount)}
+		}
+		return salaries
+	}
+	ruleSet := rules.Default()
+	ruleSet.Trend = &rules.TrendRule{StableBand: 0.01}
+	calculator := NewSalaryCalculatorWithRules(ruleSet)
+
+	t.Run("Same average, opposite directions", func(t *testing.T) {
+		rising := calculator.CalculateEmployeeStatus(series(1700, 1900, 2100))
+		falling := calculator.CalculateEmployeeStatus(series(2100, 1900, 1700))
+
+		if rising.AverageSalary.InexactFloat64() != 1900 || falling.AverageSalary.InexactFloat64() != 1900 {
+			t.Fatalf("Expected both averages to be 1900")
+		}
+		if abs(rising.Trend.Slope-200) > 0.0001 || abs(falling.Trend.Slope+200) > 0.0001 {
+			t.Errorf("Expected slopes 200 and -200, got %.4f and %.4f", rising.Trend.Slope, falling.Trend.Slope)
+		}
+		if rising.Trend.Classification != TrendRising || falling.Trend.Classification != TrendFalling {
+			t.Errorf("Expected RISING and FALLING, got %s and %s", rising.Trend.Classification, falling.Trend.Classification)
+		}
+	})
+
+	t.Run("Growth and volatility", func(t *testing.T) {
+		result := calculator.CalculateEmployeeStatus(series(2000, 2200, 2200))
+
+		// Growth rates 0.1 and 0
+		if abs(result.Trend.MonthOverMonthGrowth-0.05) > 0.0001 {
+			t.Errorf("Expected growth 0.05, got %.4f", result.Trend.MonthOverMonthGrowth)
+		}
+		if abs(result.Trend.Volatility-0.05) > 0.0001 {
+			t.Errorf("Expected volatility 0.05, got %.4f", result.Trend.Volatility)
+		}
+	})
+
+	t.Run("Gaps count as months", func(t *testing.T) {
+		salaries := []models.Salary{
+			{Year: 2024, Month: 3, Salary: decimal.NewFromFloat(2000)},
+			{Year: 2025, Month: 3, Salary: decimal.NewFromFloat(2120)},
+		}
+		result := calculator.CalculateEmployeeStatus(salaries)
+
+		if abs(result.Trend.Slope-10) > 0.0001 {
+			t.Errorf("Expected slope 10 per month, got %.4f", result.Trend.Slope)
+		}
+		if result.Trend.Classification != TrendStable {
+			t.Errorf("Expected STABLE, got %s", result.Trend.Classification)
+		}
+	})
+
+	t.Run("No classification without a trend rule", func(t *testing.T) {
+		result := NewSalaryCalculator().CalculateEmployeeStatus(series(1700, 1900, 2100))
+
+		if result.Trend == nil || result.Trend.Classification != "" {
+			t.Errorf("Expected metrics without a classification, got %+v", result.Trend)
+		}
+	})
+}
+
+// ============================================
+// Distribution Tests
+// ============================================
+
+func TestSalaryCalculator_Distribution(t *testing.T) {
+	calculator := NewSalaryCalculator()
+
+	t.Run("Statistics use final amounts", func(t *testing.T) {
+		salaries := []models.Salary{
+			{Year: 2025, Month: 5, Salary: decimal.NewFromFloat(1400)},
+			{Year: 2024, Month: 12, Salary: decimal.NewFromFloat(1000)}, // December +10%
+			{Year: 2025, Month: 3, Salary: decimal.NewFromFloat(1200)},
+			{Year: 2025, Month: 4, Salary: decimal.NewFromFloat(1600)},
+		}
+		result := calculator.CalculateEmployeeStatus(salaries)
+
+		// Final amounts sorted: 1100, 1200, 1400, 1600
+		expected := map[string]float64{
+			"lowest": 1100,
+			"median": 1300,
+			"p25":    1175,
+			"p75":    1450,
+			"stddev": 192.03,
+		}
+		actual := map[string]float64{
+			"lowest": result.LowestSalary.InexactFloat64(),
+			"median": result.MedianSalary.InexactFloat64(),
+			"p25":    result.Percentile25.InexactFloat64(),
+			"p75":    result.Percentile75.InexactFloat64(),
+			"stddev": result.StandardDeviation.InexactFloat64(),
+		}
+		for name, value := range expected {
+			if actual[name] != value {
+				t.Errorf("Expected %s %.2f, got %.2f", name, value, actual[name])
+			}
+		}
+		if result.RecordCount != 4 {
+			t.Errorf("Expected 4 records, got %d", result.RecordCount)
+		}
+		if *result.FirstPeriod != (models.Period{Year: 2024, Month: 12}) || *result.LastPeriod != (models.Period{Year: 2025, Month: 5}) {
+			t.Errorf("Expected coverage 2024-12 to 2025-05, got %s to %s", result.FirstPeriod, result.LastPeriod)
+		}
+	})
+
+	t.Run("Single salary", func(t *testing.T) {
+		result := calculator.CalculateEmployeeStatus([]models.Salary{
+			{Year: 2025, Month: 3, Salary: decimal.NewFromFloat(1500)},
+		})
+
+		if !result.MedianSalary.Equal(decimal.NewFromInt(1500)) || !result.Percentile75.Equal(decimal.NewFromInt(1500)) {
+			t.Errorf("Expected every percentile to be 1500, got %s and %s", result.MedianSalary, result.Percentile75)
+		}
+		if !result.StandardDeviation.IsZero() {
+			t.Errorf("Expected zero deviation, got %s", result.StandardDeviation)
+		}
+	})
+}
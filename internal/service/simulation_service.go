@@ -0,0 +1,62 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+// SimulationService calculates the status of hypothetical salary series with
+// the same rules as real lookups, without touching the database.
+type SimulationService struct {
+	calculator  *SalaryCalculator
+	sufficiency SufficiencyPolicy
+}
+
+func NewSimulationService(calculator *SalaryCalculator) *SimulationService {
+	return &SimulationService{calculator: calculator, sufficiency: DefaultSufficiencyPolicy()}
+}
+
+// SetSufficiencyPolicy applies the same data requirements as real lookups.
+// Hypothetical series have no age, so the maximum age rule never applies.
+func (s *SimulationService) SetSufficiencyPolicy(policy SufficiencyPolicy) {
+	s.sufficiency = policy
+}
+
+func (s *SimulationService) SimulateStatus(req models.SimulateStatusRequest) (*models.SimulateStatusResponse, error) {
+	salaries := make([]models.Salary, len(req.Salaries))
+	for i, entry := range req.Salaries {
+		salaries[i] = models.Salary{
+			Year:   entry.Year,
+			Month:  entry.Month,
+			Salary: entry.Salary,
+		}
+	}
+	// Match the order salaries are loaded in for real lookups
+	sort.Slice(salaries, func(i, j int) bool {
+		return models.Period{Year: salaries[i].Year, Month: salaries[i].Month}.
+			Before(models.Period{Year: salaries[j].Year, Month: salaries[j].Month})
+	})
+
+	var asOf models.Period
+	if n := len(salaries); n > 0 {
+		asOf = models.Period{Year: salaries[n-1].Year, Month: salaries[n-1].Month}
+	}
+	if err := s.sufficiency.check(salaries, asOf); err != nil {
+		return nil, err
+	}
+
+	calculation := s.calculator.CalculateEmployeeStatus(salaries)
+
+	response := &models.SimulateStatusResponse{
+		SalaryDetails: salaryDetails(calculation),
+		Status:        calculation.Status,
+		RuleVersions:  calculation.RuleVersions,
+		Trend:         trendDetails(calculation.Trend),
+	}
+	if req.Explain {
+		response.Explanation = buildExplanation(calculation)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulationService_SimulateStatus(t *testing.T) {
+	service := NewSimulationService(NewSalaryCalculator())
+
+	t.Run("Matches the calculator", func(t *testing.T) {
+		result, err := service.SimulateStatus(models.SimulateStatusRequest{
+			Salaries: []models.SimulatedSalary{
+				{Year: 2024, Month: 12, Salary: decimal.NewFromInt(2000)},
+				{Year: 2024, Month: 1, Salary: decimal.NewFromInt(1800)},
+				{Year: 2024, Month: 6, Salary: decimal.NewFromInt(2100)},
+			},
+			Explain: true,
+		})
+
+		require.NoError(t, err)
+		// 2200 + 1800 + 1995
+		assert.Equal(t, 5995.0, result.SalaryDetails.SumOfSalaries)
+		assert.Equal(t, 2200.0, result.SalaryDetails.HighestSalary)
+		assert.Equal(t, StatusRed, result.Status)
+		require.NotNil(t, result.Explanation)
+		// Salaries are calculated in chronological order
+		assert.Equal(t, 1, result.Explanation.Months[0].Month)
+		assert.Equal(t, 12, result.Explanation.Months[2].Month)
+	})
+
+	t.Run("Too few salaries", func(t *testing.T) {
+		_, err := service.SimulateStatus(models.SimulateStatusRequest{
+			Salaries: []models.SimulatedSalary{
+				{Year: 2024, Month: 1, Salary: decimal.NewFromInt(2500)},
+			},
+		})
+
+		var appErr *AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 422, appErr.Code)
+	})
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/rixtrayker/getemps-service/internal/repository"
+)
+
+const defaultHistoryLimit = 100
+
+type StatusHistoryService struct {
+	userRepo    repository.UserRepository
+	historyRepo repository.StatusHistoryRepository
+	auditRepo   repository.AccessAuditRepository
+}
+
+func NewStatusHistoryService(
+	userRepo repository.UserRepository,
+	historyRepo repository.StatusHistoryRepository,
+	auditRepo repository.AccessAuditRepository,
+) *StatusHistoryService {
+	return &StatusHistoryService{
+		userRepo:    userRepo,
+		historyRepo: historyRepo,
+		auditRepo:   auditRepo,
+	}
+}
+
+// List returns an employee's status snapshots, newest first. Like status
+// lookups, every request is recorded in the access audit trail.
+func (s *StatusHistoryService) List(ctx context.Context, nationalNumber string, query models.StatusHistoryQuery) ([]models.StatusSnapshot, error) {
+	snapshots, err := s.list(ctx, nationalNumber, query)
+
+	if auditErr := recordAccess(ctx, s.auditRepo, nationalNumber, err); auditErr != nil && err == nil {
+		return nil, auditErr
+	}
+
+	return snapshots, err
+}
+
+func (s *StatusHistoryService) list(ctx context.Context, nationalNumber string, query models.StatusHistoryQuery) ([]models.StatusSnapshot, error) {
+	user, err := findUser(ctx, s.userRepo, nationalNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultHistoryLimit
+	}
+
+	snapshots, err := s.historyRepo.ListByUserID(ctx, user.ID, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch status history: %w", err)
+	}
+	return snapshots, nil
+}
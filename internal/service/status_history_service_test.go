@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusHistoryService_List(t *testing.T) {
+	ctx := WithRequestInfo(context.Background(), RequestInfo{Caller: "hr-portal", AuthMethod: "jwt"})
+	user := &models.User{ID: 1, NationalNumber: "NAT1001", IsActive: true}
+
+	t.Run("Newest snapshots with the default limit", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockHistoryRepo := new(MockStatusHistoryRepository)
+		mockAuditRepo := new(MockAccessAuditRepository)
+		service := NewStatusHistoryService(mockUserRepo, mockHistoryRepo, mockAuditRepo)
+
+		snapshots := []models.StatusSnapshot{
+			{ID: 2, UserID: 1, Status: StatusRed, ComputedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: 1, UserID: 1, Status: StatusGreen, ComputedAt: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT1001").Return(user, nil)
+		mockHistoryRepo.On("ListByUserID", ctx, user.ID, defaultHistoryLimit).Return(snapshots, nil)
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(entry *models.AccessAudit) bool {
+			return entry.Caller == "hr-portal" && entry.NationalNumber == "NAT1001" && entry.ResultCode == 200
+		})).Return(nil)
+
+		result, err := service.List(ctx, "NAT1001", models.StatusHistoryQuery{})
+
+		require.NoError(t, err)
+		assert.Equal(t, snapshots, result)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown employee", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockAuditRepo := new(MockAccessAuditRepository)
+		service := NewStatusHistoryService(mockUserRepo, new(MockStatusHistoryRepository), mockAuditRepo)
+
+		mockUserRepo.On("GetByNationalNumber", ctx, "NAT9999").Return((*models.User)(nil), errors.New("user not found"))
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(entry *models.AccessAudit) bool {
+			return entry.ResultCode == 404
+		})).Return(nil)
+
+		_, err := service.List(ctx, "NAT9999", models.StatusHistoryQuery{Limit: 10})
+
+		var appErr *AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 404, appErr.Code)
+		mockAuditRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+// Sufficiency rules, reported when a status is refused.
+const (
+	RuleMinRecords        = "min_records"
+	RuleConsecutiveMonths = "consecutive_months"
+	RuleMaxGap            = "max_gap"
+	RuleMaxAge            = "max_age"
+)
+
+// SufficiencyPolicy decides whether salary data supports a confident status.
+// Zero MaxGapMonths and MaxAgeMonths disable those rules.
+type SufficiencyPolicy struct {
+	MinRecords         int
+	RequireConsecutive bool
+	// MaxGapMonths is the most missing months allowed between two records
+	MaxGapMonths int
+	// MaxAgeMonths is how many months the latest record may lag behind the
+	// end of the calculation window
+	MaxAgeMonths int
+}
+
+// DefaultSufficiencyPolicy is the service's original rule: three records.
+func DefaultSufficiencyPolicy() SufficiencyPolicy {
+	return SufficiencyPolicy{MinRecords: 3}
+}
+
+// checkCount applies the minimum record rule before salaries are fetched.
+func (p SufficiencyPolicy) checkCount(count int) error {
+	if count < p.MinRecords {
+		return insufficientData(RuleMinRecords,
+			fmt.Sprintf("at least %d salary records are required, found %d", p.MinRecords, count),
+			models.SalaryDataSummary{Records: count})
+	}
+	return nil
+}
+
+// check applies every rule to salaries sorted by month. asOf is the month the
+// latest record's age is measured from.
+func (p SufficiencyPolicy) check(salaries []models.Salary, asOf models.Period) error {
+	if err := p.checkCount(len(salaries)); err != nil {
+		return err
+	}
+	if len(salaries) == 0 {
+		return nil
+	}
+
+	summary := summarizeSalaryData(salaries, asOf)
+
+	if p.RequireConsecutive && summary.LargestGapMonths > 0 {
+		return insufficientData(RuleConsecutiveMonths,
+			fmt.Sprintf("salary records must be in consecutive months, found a gap of %d months", summary.LargestGapMonths),
+			summary)
+	}
+	if p.MaxGapMonths > 0 && summary.LargestGapMonths > p.MaxGapMonths {
+		return insufficientData(RuleMaxGap,
+			fmt.Sprintf("gaps between salary records must be at most %d months, found %d", p.MaxGapMonths, summary.LargestGapMonths),
+			summary)
+	}
+	if p.MaxAgeMonths > 0 && summary.MonthsSinceLatest > p.MaxAgeMonths {
+		return insufficientData(RuleMaxAge,
+			fmt.Sprintf("the latest salary record must be at most %d months old, it is %d", p.MaxAgeMonths, summary.MonthsSinceLatest),
+			summary)
+	}
+
+	return nil
+}
+
+func summarizeSalaryData(salaries []models.Salary, asOf models.Period) models.SalaryDataSummary {
+	first := models.Period{Year: salaries[0].Year, Month: salaries[0].Month}
+	latest := first
+	summary := models.SalaryDataSummary{Records: len(salaries), First: &first, Latest: &latest}
+
+	for _, salary := range salaries[1:] {
+		period := models.Period{Year: salary.Year, Month: salary.Month}
+		if gap := monthsBetween(latest, period) - 1; gap > summary.LargestGapMonths {
+			summary.LargestGapMonths = gap
+		}
+		latest = period
+	}
+	summary.MonthsSinceLatest = monthsBetween(latest, asOf)
+
+	return summary
+}
+
+// monthsBetween counts the months from one period to a later one.
+func monthsBetween(from, to models.Period) int {
+	return (to.Year*12 + to.Month) - (from.Year*12 + from.Month)
+}
+
+func insufficientData(rule, reason string, summary models.SalaryDataSummary) *AppError {
+	return &AppError{
+		Code:    422,
+		Message: "INSUFFICIENT_DATA",
+		Details: &models.InsufficientDataDetails{
+			Rule:   rule,
+			Reason: reason,
+			Data:   summary,
+		},
+	}
+}
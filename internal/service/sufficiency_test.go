@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func salariesIn(periods ...models.Period) []models.Salary {
+	salaries := make([]models.Salary, len(periods))
+	for i, period := range periods {
+		salaries[i] = models.Salary{Year: period.Year, Month: period.Month, Salary: decimal.NewFromInt(2000)}
+	}
+	return salaries
+}
+
+func TestSufficiencyPolicy_Check(t *testing.T) {
+	asOf := models.Period{Year: 2025, Month: 6}
+	consecutive := salariesIn(
+		models.Period{Year: 2025, Month: 3},
+		models.Period{Year: 2025, Month: 4},
+		models.Period{Year: 2025, Month: 5},
+	)
+	spread := salariesIn(
+		models.Period{Year: 2015, Month: 1},
+		models.Period{Year: 2020, Month: 1},
+		models.Period{Year: 2024, Month: 12},
+	)
+
+	testCases := []struct {
+		name     string
+		policy   SufficiencyPolicy
+		salaries []models.Salary
+		rule     string
+	}{
+		{"Default policy accepts three records", DefaultSufficiencyPolicy(), spread, ""},
+		{"Too few records", SufficiencyPolicy{MinRecords: 4}, consecutive, RuleMinRecords},
+		{"Consecutive months", SufficiencyPolicy{MinRecords: 3, RequireConsecutive: true}, consecutive, ""},
+		{"Gap with consecutive months required", SufficiencyPolicy{MinRecords: 3, RequireConsecutive: true}, spread, RuleConsecutiveMonths},
+		{"Gap within the limit", SufficiencyPolicy{MinRecords: 3, MaxGapMonths: 60}, spread, ""},
+		{"Gap above the limit", SufficiencyPolicy{MinRecords: 3, MaxGapMonths: 12}, spread, RuleMaxGap},
+		{"Recent data", SufficiencyPolicy{MinRecords: 3, MaxAgeMonths: 1}, consecutive, ""},
+		{"Stale data", SufficiencyPolicy{MinRecords: 3, MaxAgeMonths: 3}, spread, RuleMaxAge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.check(tc.salaries, asOf)
+
+			if tc.rule == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var appErr *AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, 422, appErr.Code)
+			assert.Equal(t, "INSUFFICIENT_DATA", appErr.Message)
+			details, ok := appErr.Details.(*models.InsufficientDataDetails)
+			require.True(t, ok)
+			assert.Equal(t, tc.rule, details.Rule)
+		})
+	}
+}
+
+func TestSufficiencyPolicy_Details(t *testing.T) {
+	spread := salariesIn(
+		models.Period{Year: 2015, Month: 1},
+		models.Period{Year: 2020, Month: 1},
+		models.Period{Year: 2024, Month: 12},
+	)
+	policy := SufficiencyPolicy{MinRecords: 3, MaxGapMonths: 24}
+
+	err := policy.check(spread, models.Period{Year: 2025, Month: 6})
+
+	var appErr *AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, &models.InsufficientDataDetails{
+		Rule:   RuleMaxGap,
+		Reason: "gaps between salary records must be at most 24 months, found 59",
+		Data: models.SalaryDataSummary{
+			Records:           3,
+			First:             &models.Period{Year: 2015, Month: 1},
+			Latest:            &models.Period{Year: 2024, Month: 12},
+			LargestGapMonths:  59,
+			MonthsSinceLatest: 6,
+		},
+	}, appErr.Details)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"math"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	TrendRising  = "RISING"
+	TrendStable  = "STABLE"
+	TrendFalling = "FALLING"
+)
+
+// TrendMetrics describe the direction of a chronological salary series.
+// They are statistics rather than amounts, so they are computed in floating
+// point.
+type TrendMetrics struct {
+	// Slope is the least-squares change in salary per calendar month
+	Slope float64
+	// MonthOverMonthGrowth is the mean growth rate between consecutive records
+	MonthOverMonthGrowth float64
+	// Volatility is the standard deviation of those growth rates
+	Volatility float64
+	// Classification is empty unless the rules define a trend band
+	Classification string
+}
+
+// calculateTrend analyses the final amounts of salaries, which must be in
+// chronological order. Months are placed on a calendar axis, so gaps between
+// records lower the slope accordingly.
+func (sc *SalaryCalculator) calculateTrend(salaries []models.Salary, amounts []decimal.Decimal) *TrendMetrics {
+	if len(amounts) < 2 {
+		return nil
+	}
+
+	n := float64(len(amounts))
+	xs := make([]float64, len(amounts))
+	ys := make([]float64, len(amounts))
+	var sumX, sumY float64
+	for i, salary := range salaries {
+		xs[i] = float64(salary.Year*12 + salary.Month)
+		ys[i] = amounts[i].InexactFloat64()
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var covariance, variance float64
+	for i := range xs {
+		covariance += (xs[i] - meanX) * (ys[i] - meanY)
+		variance += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+
+	trend := &TrendMetrics{}
+	if variance > 0 {
+		trend.Slope = covariance / variance
+	}
+
+	var growth []float64
+	for i := 1; i < len(ys); i++ {
+		if ys[i-1] != 0 {
+			growth = append(growth, (ys[i]-ys[i-1])/ys[i-1])
+		}
+	}
+	if len(growth) > 0 {
+		var sum float64
+		for _, g := range growth {
+			sum += g
+		}
+		trend.MonthOverMonthGrowth = sum / float64(len(growth))
+
+		var squares float64
+		for _, g := range growth {
+			squares += (g - trend.MonthOverMonthGrowth) * (g - trend.MonthOverMonthGrowth)
+		}
+		trend.Volatility = math.Sqrt(squares / float64(len(growth)))
+	}
+
+	if rule := sc.rules.Trend; rule != nil && meanY > 0 {
+		relative := trend.Slope / meanY
+		switch {
+		case relative > rule.StableBand:
+			trend.Classification = TrendRising
+		case relative < -rule.StableBand:
+			trend.Classification = TrendFalling
+		default:
+			trend.Classification = TrendStable
+		}
+	}
+
+	return trend
+}
+
+// trendDetails rounds trend metrics for the response.
+func trendDetails(trend *TrendMetrics) *models.TrendDetails {
+	if trend == nil {
+		return nil
+	}
+	return &models.TrendDetails{
+		Slope:                roundTo(trend.Slope, moneyPlaces),
+		MonthOverMonthGrowth: roundTo(trend.MonthOverMonthGrowth, ratioPlaces),
+		Volatility:           roundTo(trend.Volatility, ratioPlaces),
+		Classification:       trend.Classification,
+	}
+}
+
+// ratioPlaces is the precision of reported rates such as growth.
+const ratioPlaces = 4
+
+func roundTo(value float64, places int32) float64 {
+	return decimal.NewFromFloat(value).Round(places).InexactFloat64()
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"time"
+
+	"github.com/rixtrayker/getemps-service/internal/models"
+)
+
+// Window restricts a status lookup to a range of months, either From to To
+// inclusive or the trailing LastMonths including the current month. The zero
+// value uses the service default.
+type Window struct {
+	From       models.Period
+	To         models.Period
+	LastMonths int
+}
+
+// periodRange is an inclusive range of months.
+type periodRange struct {
+	from models.Period
+	to   models.Period
+}
+
+func (r *periodRange) cacheKey(base string) string {
+	if r == nil {
+		return base
+	}
+	return base + ":" + r.from.String() + ":" + r.to.String()
+}
+
+// resolve returns the months to compute over, or nil for the full history.
+func (w Window) resolve(now time.Time, defaultMonths int) *periodRange {
+	switch {
+	case w.From != (models.Period{}):
+		return &periodRange{from: w.From, to: w.To}
+	case w.LastMonths > 0:
+		return trailingMonths(now, w.LastMonths)
+	case defaultMonths > 0:
+		return trailingMonths(now, defaultMonths)
+	}
+	return nil
+}
+
+func trailingMonths(now time.Time, months int) *periodRange {
+	current := models.PeriodOf(now)
+	return &periodRange{from: current.AddMonths(1 - months), to: current}
+}
@@ -1,17 +1,59 @@
 package validator
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/rixtrayker/getemps-service/internal/models"
 )
 
 func ValidateEmployeeRequest(req models.EmployeeRequest) error {
-	return validation.ValidateStruct(&req,
+	if err := validation.ValidateStruct(&req,
 		validation.Field(&req.NationalNumber,
 			validation.Required.Error("National number is required"),
 			validation.Length(3, 50).Error("Invalid national number format"),
 		),
-	)
+		validation.Field(&req.FromYear, validation.Min(1).Error("Invalid fromYear")),
+		validation.Field(&req.FromMonth,
+			validation.Min(1).Error("fromMonth must be between 1 and 12"),
+			validation.Max(12).Error("fromMonth must be between 1 and 12"),
+		),
+		validation.Field(&req.ToYear, validation.Min(1).Error("Invalid toYear")),
+		validation.Field(&req.ToMonth,
+			validation.Min(1).Error("toMonth must be between 1 and 12"),
+			validation.Max(12).Error("toMonth must be between 1 and 12"),
+		),
+		validation.Field(&req.LastMonths,
+			validation.Min(0).Error("lastMonths must not be negative"),
+			validation.Max(600).Error("lastMonths must be at most 600"),
+		),
+	); err != nil {
+		return err
+	}
+
+	return validateWindow(req)
+}
+
+func validateWindow(req models.EmployeeRequest) error {
+	from := models.Period{Year: req.FromYear, Month: req.FromMonth}
+	to := models.Period{Year: req.ToYear, Month: req.ToMonth}
+	hasRange := from != models.Period{} || to != models.Period{}
+
+	if hasRange && req.LastMonths != 0 {
+		return errors.New("Use either a from/to range or lastMonths, not both")
+	}
+	if !hasRange {
+		return nil
+	}
+	if from.Year == 0 || from.Month == 0 || to.Year == 0 || to.Month == 0 {
+		return errors.New("fromYear, fromMonth, toYear and toMonth are all required for a range")
+	}
+	if to.Before(from) {
+		return errors.New("The range must not end before it starts")
+	}
+	return nil
 }
 
 func IsValidNationalNumber(nationalNumber string) bool {
@@ -23,3 +65,128 @@ func IsValidNationalNumber(nationalNumber string) bool {
 	// For now, we just check length and non-empty
 	return nationalNumber != ""
 }
+
+var scopePattern = regexp.MustCompile(`^[a-z]+(:[a-z]+)?$`)
+
+func ValidateCreateAPIKeyRequest(req models.CreateAPIKeyRequest) error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.Owner,
+			validation.Required.Error("Owner is required"),
+			validation.Length(1, 100).Error("Owner must be at most 100 characters"),
+		),
+		validation.Field(&req.Scopes,
+			validation.Each(validation.Match(scopePattern).Error("Invalid scope format")),
+		),
+		validation.Field(&req.Tier,
+			validation.Length(0, 50).Error("Tier must be at most 50 characters"),
+		),
+		validation.Field(&req.TTLHours,
+			validation.Min(0).Error("TTL must not be negative"),
+		),
+	)
+}
+
+func ValidateCreateOAuthClientRequest(req models.CreateOAuthClientRequest) error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(1, 100).Error("Name must be at most 100 characters"),
+		),
+		validation.Field(&req.Scopes,
+			validation.Required.Error("At least one scope is required"),
+			validation.Each(validation.Match(scopePattern).Error("Invalid scope format")),
+		),
+		validation.Field(&req.Tier,
+			validation.Length(0, 50).Error("Tier must be at most 50 characters"),
+		),
+	)
+}
+
+func ValidateStatusHistoryQuery(nationalNumber string, query models.StatusHistoryQuery) error {
+	if !IsValidNationalNumber(nationalNumber) {
+		return errors.New("Invalid national number format")
+	}
+
+	return validation.ValidateStruct(&query,
+		validation.Field(&query.Limit,
+			validation.Min(0).Error("Limit must not be negative"),
+			validation.Max(1000).Error("Limit must be at most 1000"),
+		),
+	)
+}
+
+const maxSimulatedSalaries = 600
+
+func ValidateSimulateStatusRequest(req models.SimulateStatusRequest) error {
+	if err := validation.ValidateStruct(&req,
+		validation.Field(&req.Salaries,
+			validation.Required.Error("At least one salary is required"),
+			validation.Length(0, maxSimulatedSalaries).Error(fmt.Sprintf("At most %d salaries can be simulated", maxSimulatedSalaries)),
+		),
+	); err != nil {
+		return err
+	}
+
+	seen := make(map[models.Period]bool, len(req.Salaries))
+	for i, salary := range req.Salaries {
+		if err := validation.ValidateStruct(&salary,
+			validation.Field(&salary.Year,
+				validation.Required.Error("Year is required"),
+				validation.Min(1).Error("Invalid year"),
+			),
+			validation.Field(&salary.Month,
+				validation.Required.Error("Month is required"),
+				validation.Min(1).Error("Month must be between 1 and 12"),
+				validation.Max(12).Error("Month must be between 1 and 12"),
+			),
+		); err != nil {
+			return fmt.Errorf("salaries[%d]: %w", i, err)
+		}
+		if salary.Salary.IsNegative() {
+			return fmt.Errorf("salaries[%d]: salary must not be negative", i)
+		}
+
+		period := models.Period{Year: salary.Year, Month: salary.Month}
+		if seen[period] {
+			return fmt.Errorf("salaries[%d]: duplicate salary for %s", i, period)
+		}
+		seen[period] = true
+	}
+
+	return nil
+}
+
+func ValidateBatchStatusRequest(req models.BatchStatusRequest, maxSize int) error {
+	if err := validation.ValidateStruct(&req,
+		validation.Field(&req.NationalNumbers,
+			validation.Required.Error("At least one national number is required"),
+			validation.Length(0, maxSize).Error(fmt.Sprintf("At most %d national numbers can be looked up at once", maxSize)),
+		),
+	); err != nil {
+		return err
+	}
+
+	for i, nationalNumber := range req.NationalNumbers {
+		if !IsValidNationalNumber(nationalNumber) {
+			return fmt.Errorf("NationalNumbers[%d]: Invalid national number format", i)
+		}
+	}
+
+	return nil
+}
+
+func ValidateAccessAuditQuery(query models.AccessAuditQuery) error {
+	if query.NationalNumber == "" && query.Caller == "" {
+		return errors.New("Either nationalNumber or caller is required")
+	}
+
+	return validation.ValidateStruct(&query,
+		validation.Field(&query.NationalNumber,
+			validation.Length(3, 50).Error("Invalid national number format"),
+		),
+		validation.Field(&query.Limit,
+			validation.Min(0).Error("Limit must not be negative"),
+			validation.Max(1000).Error("Limit must be at most 1000"),
+		),
+	)
+}
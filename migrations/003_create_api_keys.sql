@@ -0,0 +1,16 @@
+-- Create api_keys table
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    key_prefix VARCHAR(16) NOT NULL,        -- first characters of the key, for identification only
+    key_hash CHAR(64) UNIQUE NOT NULL,      -- hex HMAC-SHA256 of the key with the server pepper
+    owner VARCHAR(100) NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',        -- space-delimited scopes
+    expires_at TIMESTAMP,
+    revoked BOOLEAN DEFAULT FALSE NOT NULL,
+    revoked_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for api_keys table
+CREATE INDEX idx_api_keys_owner ON api_keys(owner);
@@ -0,0 +1,10 @@
+-- Create oauth_clients table
+CREATE TABLE oauth_clients (
+    id SERIAL PRIMARY KEY,
+    client_id VARCHAR(64) UNIQUE NOT NULL,
+    secret_hash CHAR(64) NOT NULL,          -- hex HMAC-SHA256 of the secret with the server pepper
+    name VARCHAR(100) NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',        -- space-delimited scopes the client may request
+    is_active BOOLEAN DEFAULT TRUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- Add rate limit tier to api_keys
+ALTER TABLE api_keys ADD COLUMN tier VARCHAR(50) NOT NULL DEFAULT '';
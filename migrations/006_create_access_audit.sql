@@ -0,0 +1,16 @@
+-- Create access_audit table
+CREATE TABLE access_audit (
+    id BIGSERIAL PRIMARY KEY,
+    caller TEXT NOT NULL,                   -- authenticated principal subject, any length
+    auth_method VARCHAR(20) NOT NULL,
+    national_number VARCHAR(50) NOT NULL,
+    result_code INTEGER NOT NULL,           -- HTTP status returned to the caller
+    client_ip VARCHAR(45) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Create indexes for lookups by employee and by caller; callers are indexed
+-- by hash so long subjects cannot exceed the btree row size
+CREATE INDEX idx_access_audit_national_number ON access_audit(national_number, created_at DESC);
+CREATE INDEX idx_access_audit_caller ON access_audit(md5(caller), created_at DESC);
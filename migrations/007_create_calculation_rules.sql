@@ -0,0 +1,26 @@
+-- Create calculation_rules table
+CREATE TABLE calculation_rules (
+    id SERIAL PRIMARY KEY,
+    version VARCHAR(50) UNIQUE NOT NULL,
+    definition JSONB NOT NULL,              -- rule set document, same shape as the JSON rule file
+    is_active BOOLEAN DEFAULT FALSE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Only one rule set can be active at a time
+CREATE UNIQUE INDEX idx_calculation_rules_active ON calculation_rules(is_active) WHERE is_active;
+
+-- Seed the rules the service has always applied
+INSERT INTO calculation_rules (version, definition, is_active) VALUES (
+    'default',
+    '{
+        "version": "default",
+        "adjustments": [
+            {"name": "December holiday bonus", "months": [12], "multiplier": 1.10},
+            {"name": "Summer deduction", "months": [6, 7, 8], "multiplier": 0.95}
+        ],
+        "tax": {"threshold": 10000, "rate": 0.07},
+        "status": {"threshold": 2000}
+    }',
+    TRUE
+);
@@ -0,0 +1,12 @@
+-- Add currency to salaries; existing salaries are in the reporting currency
+ALTER TABLE salaries ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'JOD';
+
+-- Create exchange_rates table
+CREATE TABLE exchange_rates (
+    currency VARCHAR(3) NOT NULL,
+    year INT NOT NULL,
+    month INT NOT NULL CHECK (month BETWEEN 1 AND 12),
+    rate DECIMAL(18, 8) NOT NULL CHECK (rate > 0),  -- value of one unit in the reporting currency
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (currency, year, month)
+);
@@ -0,0 +1,16 @@
+-- Create employee_status_history table
+CREATE TABLE employee_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    status VARCHAR(10) NOT NULL,            -- GREEN, ORANGE or RED
+    average_salary DECIMAL(12, 2) NOT NULL,
+    highest_salary DECIMAL(12, 2) NOT NULL,
+    sum_of_salaries DECIMAL(14, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    rule_version VARCHAR(50) NOT NULL,
+    computed_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create index for an employee's history, newest first
+CREATE INDEX idx_status_history_user ON employee_status_history(user_id, computed_at DESC);
@@ -0,0 +1,10 @@
+-- Version calculation rules by effective month; every active rule set is in
+-- force from its effective_from month until the next one takes over
+ALTER TABLE calculation_rules ADD COLUMN effective_from DATE CHECK (EXTRACT(DAY FROM effective_from) = 1);
+
+DROP INDEX idx_calculation_rules_active;
+
+-- Only one active rule set can take effect in a given month, and only one
+-- can have no effective date
+CREATE UNIQUE INDEX idx_calculation_rules_effective ON calculation_rules(effective_from) WHERE is_active;
+CREATE UNIQUE INDEX idx_calculation_rules_undated ON calculation_rules(is_active) WHERE is_active AND effective_from IS NULL;
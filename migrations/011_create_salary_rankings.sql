@@ -0,0 +1,14 @@
+-- Create salary_rankings table, refreshed periodically with the adjusted
+-- average salary of every active employee
+CREATE TABLE salary_rankings (
+    user_id INT PRIMARY KEY,
+    status VARCHAR(10) NOT NULL,            -- GREEN, ORANGE or RED
+    average_salary DECIMAL(12, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    refreshed_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes for ranking overall and within a status
+CREATE INDEX idx_salary_rankings_average ON salary_rankings(average_salary);
+CREATE INDEX idx_salary_rankings_status_average ON salary_rankings(status, average_salary);
@@ -0,0 +1,20 @@
+-- Create departments table; departments without a parent are top level
+CREATE TABLE departments (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    parent_id INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (parent_id) REFERENCES departments(id) ON DELETE RESTRICT,
+    CHECK (parent_id <> id)
+);
+
+-- Create index for walking down the hierarchy
+CREATE INDEX idx_departments_parent ON departments(parent_id);
+
+-- Add department and manager references to users
+ALTER TABLE users
+    ADD COLUMN department_id INT REFERENCES departments(id) ON DELETE SET NULL,
+    ADD COLUMN manager_id INT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_users_department ON users(department_id);
+CREATE INDEX idx_users_manager ON users(manager_id);
@@ -0,0 +1,14 @@
+-- Store percentiles computed at refresh time, ranked overall and within the
+-- employee's department, so lookups read a single row
+ALTER TABLE salary_rankings
+    ADD COLUMN department_id INT REFERENCES departments(id) ON DELETE SET NULL,
+    ADD COLUMN overall_percentile DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN department_percentile DECIMAL(5, 2),  -- NULL without a department
+    ADD COLUMN employees INT NOT NULL DEFAULT 0,
+    ADD COLUMN department_employees INT NOT NULL DEFAULT 0;
+
+DROP INDEX idx_salary_rankings_average;
+DROP INDEX idx_salary_rankings_status_average;
+
+-- Create index for department summaries
+CREATE INDEX idx_salary_rankings_department ON salary_rankings(department_id);
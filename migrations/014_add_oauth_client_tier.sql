@@ -0,0 +1,3 @@
+-- Add rate limit tier to oauth_clients; tokens issued to a client are
+-- limited by the tier recorded here, never by a claim in the token
+ALTER TABLE oauth_clients ADD COLUMN tier VARCHAR(50) NOT NULL DEFAULT '';
@@ -9,6 +9,7 @@ set -e
 BASE_URL="${BASE_URL:-http://localhost:8080}"
 API_ENDPOINT="$BASE_URL/api/GetEmpStatus"
 HEALTH_ENDPOINT="$BASE_URL/health"
+API_SECRET_KEY="${API_SECRET_KEY:-your-secret-key-here}"
 
 # Colors
 RED='\033[0;31m'
@@ -34,6 +35,20 @@ log_error() {
     echo -e "${RED}[ERROR]${NC} $1"
 }
 
+base64url() {
+    openssl base64 -A | tr '+/' '-_' | tr -d '='
+}
+
+# Mint a short-lived HS256 token signed with API_SECRET_KEY
+generate_token() {
+    local now header payload signature
+    now=$(date +%s)
+    header=$(printf '{"alg":"HS256","typ":"JWT"}' | base64url)
+    payload=$(printf '{"sub":"test-api-script","scope":"status:read","iat":%d,"exp":%d}' "$now" "$((now + 300))" | base64url)
+    signature=$(printf '%s.%s' "$header" "$payload" | openssl dgst -sha256 -hmac "$API_SECRET_KEY" -binary | base64url)
+    printf '%s.%s.%s' "$header" "$payload" "$signature"
+}
+
 # Test function
 test_endpoint() {
     local name="$1"
@@ -81,6 +96,8 @@ main() {
     log_info "Base URL: $BASE_URL"
     echo ""
 
+    AUTH_TOKEN="${AUTH_TOKEN:-$(generate_token)}"
+
     # Test 1: Health Check
     test_endpoint \
         "Health Check" \
@@ -106,6 +123,15 @@ main() {
         "401" \
         "Authorization: InvalidFormat"
 
+    # Test 3b: Unsigned/Garbage Token (401)
+    test_endpoint \
+        "Invalid Token Signature" \
+        "POST" \
+        "$API_ENDPOINT" \
+        '{"NationalNumber": "NAT1001"}' \
+        "401" \
+        "Authorization: Bearer xxxxxxxx"
+
     # Test 4: Valid Employee (RED status) - WITH AUTH
     test_endpoint \
         "Valid Employee - RED Status" \
@@ -113,7 +139,7 @@ main() {
         "$API_ENDPOINT" \
         '{"NationalNumber": "NAT1001"}' \
         "200" \
-        "Authorization: Bearer test-token-12345"
+        "Authorization: Bearer $AUTH_TOKEN"
 
     # Test 5: Valid Employee (GREEN status) - WITH AUTH
     test_endpoint \
@@ -122,7 +148,7 @@ main() {
         "$API_ENDPOINT" \
         '{"NationalNumber": "NAT1004"}' \
         "200" \
-        "Authorization: Bearer test-token-12345"
+        "Authorization: Bearer $AUTH_TOKEN"
 
     # Test 6: Valid Employee (ORANGE status) - WITH AUTH
     test_endpoint \
@@ -131,7 +157,7 @@ main() {
         "$API_ENDPOINT" \
         '{"NationalNumber": "NAT1002"}' \
         "200" \
-        "Authorization: Bearer test-token-12345"
+        "Authorization: Bearer $AUTH_TOKEN"
 
     # Test 7: Invalid National Number - WITH AUTH
     test_endpoint \
@@ -140,7 +166,7 @@ main() {
         "$API_ENDPOINT" \
         '{"NationalNumber": "NAT9999"}' \
         "404" \
-        "Authorization: Bearer test-token-12345"
+        "Authorization: Bearer $AUTH_TOKEN"
 
     # Test 8: Inactive User - WITH AUTH
     test_endpoint \
@@ -149,7 +175,7 @@ main() {
         "$API_ENDPOINT" \
         '{"NationalNumber": "NAT1003"}' \
         "406" \
-        "Authorization: Bearer test-token-12345"
+        "Authorization: Bearer $AUTH_TOKEN"
 
     # Test 9: Insufficient Data - WITH AUTH
     test_endpoint \
@@ -158,7 +184,7 @@ main() {
         "$API_ENDPOINT" \
         '{"NationalNumber": "NAT1011"}' \
         "422" \
-        "Authorization: Bearer test-token-12345"
+        "Authorization: Bearer $AUTH_TOKEN"
 
     # Test 10: Invalid Request Format - WITH AUTH
     test_endpoint \
@@ -167,7 +193,7 @@ main() {
         "$API_ENDPOINT" \
         '{"InvalidField": "NAT1001"}' \
         "400" \
-        "Authorization: Bearer test-token-12345"
+        "Authorization: Bearer $AUTH_TOKEN"
 
     # Test 11: Empty Request Body - WITH AUTH
     test_endpoint \
@@ -176,7 +202,7 @@ main() {
         "$API_ENDPOINT" \
         '{}' \
         "400" \
-        "Authorization: Bearer test-token-12345"
+        "Authorization: Bearer $AUTH_TOKEN"
 
     # Test 12: Malformed JSON - WITH AUTH
     test_endpoint \
@@ -185,7 +211,7 @@ main() {
         "$API_ENDPOINT" \
         '{"NationalNumber": "NAT1001"' \
         "400" \
-        "Authorization: Bearer test-token-12345"
+        "Authorization: Bearer $AUTH_TOKEN"
 
     log_success "All API tests completed!"
 }